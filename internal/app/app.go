@@ -1,14 +1,19 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/download"
 	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/player"
 	"github.com/lakerszhy/rssx/internal/rss"
 	"github.com/lakerszhy/rssx/internal/view"
 	"github.com/lakerszhy/rssx/internal/view/dialog"
@@ -16,14 +21,24 @@ import (
 	"github.com/pkg/browser"
 )
 
+const (
+	// refreshTickInterval is how often feeds due for refresh are checked.
+	refreshTickInterval = time.Minute
+	// watchInterval is how often writes of other processes are checked.
+	watchInterval = 2 * time.Second
+)
+
 type app struct {
 	windowWidth  int
 	windowHeight int
 
-	dir    string
-	cfg    *config.App
-	logger *slog.Logger
-	repo   rss.Repo
+	dir     string
+	cfg     *config.App
+	logger  *slog.Logger
+	repo    rss.Repo
+	fetcher *rss.Fetcher
+	manager *download.Manager
+	player  *player.Player
 
 	feedPanel    panel.Feed
 	itemPanel    panel.Item
@@ -35,20 +50,25 @@ type app struct {
 
 	loadFeedsMsg message.LoadFeeds
 	refreshMsg   message.Refresh
+	// queuedFeeds are refreshed once the running refresh finished.
+	queuedFeeds []rss.Feed
 }
 
-func New(dir string, cfg *config.App, logger *slog.Logger,
-	repo rss.Repo, version string) tea.Model {
+func New(dir string, cfg *config.App, logger *slog.Logger, repo rss.Repo,
+	fetcher *rss.Fetcher, manager *download.Manager, player *player.Player, version string) tea.Model {
 	return app{
 		dir:          dir,
 		cfg:          cfg,
 		logger:       logger,
 		repo:         repo,
+		fetcher:      fetcher,
+		manager:      manager,
+		player:       player,
 		focus:        focusFeed,
 		loadFeedsMsg: message.NewLoadFeedsInProgress(),
 		feedPanel:    panel.NewFeed(cfg, logger, repo),
 		itemPanel:    panel.NewItem(cfg, logger, repo),
-		previewPanel: panel.NewPreview(cfg, logger),
+		previewPanel: panel.NewPreview(cfg, logger, repo),
 		statusBar:    view.NewStatusBar(cfg, logger, version),
 	}
 }
@@ -57,6 +77,8 @@ func (a app) Init() tea.Cmd {
 	return tea.Batch(
 		tea.SetWindowTitle("RssX"),
 		message.LoadFeedsCmd(a.repo),
+		message.WaitDownloadsCmd(a.manager),
+		message.WaitPlayedCmd(a.player),
 	)
 }
 
@@ -70,10 +92,20 @@ func (a app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a.onDeleteFeedMsg(msg)
 	case message.RenameFeed:
 		return a.onRenameFeedMsg(msg)
+	case message.UpdateFeedRequest:
+		return a.onUpdateFeedRequestMsg(msg)
+	case message.UpdateFeedFilter:
+		return a.onUpdateFeedFilterMsg(msg)
+	case message.UpdateFeedRetention:
+		return a.onUpdateFeedRetentionMsg(msg)
 	case message.LoadFeeds:
 		return a.onLoadFeedsMsg(msg)
 	case message.SelectFeed:
 		return a.onSelectFeedMsg(msg)
+	case message.LoadItems:
+		return a.onLoadItemsMsg(msg)
+	case message.LoadCounts:
+		return a.onLoadCountsMsg(msg)
 	case message.SelectFeedItem:
 		return a.onSelectFeedItemMsg(msg)
 	case message.ToogleRead:
@@ -82,16 +114,40 @@ func (a app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a.onMarkAllReadMsg(msg)
 	case message.ToogleStarred:
 		return a.onToogleStarredMsg(msg)
+	case message.MarkUpdateSeen:
+		return a.onMarkUpdateSeenMsg(msg)
+	case message.ToogleUpdatedUnread:
+		return a.onToogleUpdatedUnreadMsg(msg)
+	case message.ToogleFullContent:
+		return a.onToogleFullContentMsg(msg)
+	case message.ExtractFullContent:
+		return a.onExtractFullContentMsg(msg)
 	case message.ParseMD:
 		return a.onParseMDMsg(msg)
+	case message.Backfill:
+		return a.onBackfillMsg(msg)
+	case message.QueuePlay:
+		return a.onQueuePlayMsg(msg)
+	case message.Played:
+		return a.onPlayedMsg(msg)
+	case message.MarkPlayed:
+		return a.onMarkPlayedMsg(msg)
+	case message.QueueDownload:
+		return a.onQueueDownloadMsg(msg)
+	case message.Downloads:
+		return a.onDownloadsMsg(msg)
 	case message.Refresh:
 		return a.onRefreshMsg(msg)
 	case message.RefreshTick:
 		return a.onRefreshTickMsg(msg)
+	case message.WatchChanges:
+		return a.onWatchChangesMsg(msg)
 	case message.Export:
 		return a.onExportMsg(msg)
 	case message.Import:
 		return a.onImportMsg(msg)
+	case message.OpenItem:
+		return a.onOpenItemMsg(msg)
 	case message.Tips:
 		a.statusBar, cmd = a.statusBar.Update(msg)
 		return a, cmd
@@ -113,7 +169,9 @@ func (a app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (a app) onAddFeedMsg(msg message.AddFeed) (app, tea.Cmd) {
 	// When add feed is in progress, user close add feed dialog,
 	// we should not update dialog.
-	if _, ok := a.dialog.(dialog.AddFeed); !ok {
+	switch a.dialog.(type) {
+	case dialog.AddFeed, dialog.AddScrapedFeed:
+	default:
 		return a, nil
 	}
 
@@ -187,6 +245,75 @@ func (a app) onRenameFeedMsg(msg message.RenameFeed) (app, tea.Cmd) {
 	return a, cmd
 }
 
+func (a app) onUpdateFeedRequestMsg(msg message.UpdateFeedRequest) (app, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if a.dialog == nil && msg.IsInitial() {
+		a.dialog = dialog.NewFeedRequest(a.cfg, a.repo)
+		a.dialog, cmd = a.dialog.Update(msg)
+		return a, cmd
+	}
+
+	if _, ok := a.dialog.(dialog.FeedRequest); !ok {
+		return a, nil
+	}
+
+	if msg.IsSuccessful() {
+		a.dialog = nil
+		a.feedPanel, cmd = a.feedPanel.Update(msg)
+		return a, cmd
+	}
+
+	a.dialog, cmd = a.dialog.Update(msg)
+	return a, cmd
+}
+
+func (a app) onUpdateFeedFilterMsg(msg message.UpdateFeedFilter) (app, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if a.dialog == nil && msg.IsInitial() {
+		a.dialog = dialog.NewFeedFilter(a.cfg, a.repo)
+		a.dialog, cmd = a.dialog.Update(msg)
+		return a, cmd
+	}
+
+	if _, ok := a.dialog.(dialog.FeedFilter); !ok {
+		return a, nil
+	}
+
+	if msg.IsSuccessful() {
+		a.dialog = nil
+		a.feedPanel, cmd = a.feedPanel.Update(msg)
+		return a, cmd
+	}
+
+	a.dialog, cmd = a.dialog.Update(msg)
+	return a, cmd
+}
+
+func (a app) onUpdateFeedRetentionMsg(msg message.UpdateFeedRetention) (app, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if a.dialog == nil && msg.IsInitial() {
+		a.dialog = dialog.NewFeedRetention(a.cfg, a.repo)
+		a.dialog, cmd = a.dialog.Update(msg)
+		return a, cmd
+	}
+
+	if _, ok := a.dialog.(dialog.FeedRetention); !ok {
+		return a, nil
+	}
+
+	if msg.IsSuccessful() {
+		a.dialog = nil
+		a.feedPanel, cmd = a.feedPanel.Update(msg)
+		return a, cmd
+	}
+
+	a.dialog, cmd = a.dialog.Update(msg)
+	return a, cmd
+}
+
 func (a app) onLoadFeedsMsg(msg message.LoadFeeds) (app, tea.Cmd) {
 	a.loadFeedsMsg = msg
 
@@ -197,10 +324,26 @@ func (a app) onLoadFeedsMsg(msg message.LoadFeeds) (app, tea.Cmd) {
 		a.feedPanel, cmd = a.feedPanel.Update(msg)
 		cmds = append(cmds, cmd)
 
-		cmd = message.RefreshTickCmd(a.cfg.RefreshInterval)
+		cmd = message.RefreshTickCmd(refreshTickInterval)
 		cmds = append(cmds, cmd)
 
-		cmd = message.RefreshCmd(msg.Feeds, a.repo)
+		cmd = message.WatchChangesCmd(watchInterval, a.repo)
+		cmds = append(cmds, cmd)
+
+		cmd = a.refreshCmd(a.feedPanel.DueFeeds(time.Now()))
+		cmds = append(cmds, cmd)
+
+		// Continue downloads of the last run.
+		feeds := a.feedPanel.NormalFeeds()
+		cmd = func() tea.Msg {
+			items, err := a.repo.GetDownloadItems(context.Background())
+			if err != nil {
+				a.logger.Error("load downloads failed", "err", err)
+				return nil
+			}
+			a.manager.Restore(items, feeds)
+			return nil
+		}
 		cmds = append(cmds, cmd)
 	}
 	return a, tea.Batch(cmds...)
@@ -208,10 +351,44 @@ func (a app) onLoadFeedsMsg(msg message.LoadFeeds) (app, tea.Cmd) {
 
 func (a app) onSelectFeedMsg(msg message.SelectFeed) (app, tea.Cmd) {
 	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
 	a.itemPanel, cmd = a.itemPanel.Update(msg)
+	cmds = append(cmds, cmd)
+
+	// The opened item is read in the item panel.
+	if msg.Item != nil {
+		a.focus = focusItem
+		cmds = append(cmds, a.updateFocus())
+	}
+
+	return a, tea.Batch(cmds...)
+}
+
+func (a app) onOpenItemMsg(msg message.OpenItem) (app, tea.Cmd) {
+	a.dialog = nil
+	cmd := a.feedPanel.OpenItem(msg.FeedItem)
 	return a, cmd
 }
 
+func (a app) onLoadItemsMsg(msg message.LoadItems) (app, tea.Cmd) {
+	var cmd tea.Cmd
+	a.itemPanel, cmd = a.itemPanel.Update(msg)
+	return a, cmd
+}
+
+func (a app) onLoadCountsMsg(msg message.LoadCounts) (app, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	a.feedPanel, cmd = a.feedPanel.Update(msg)
+	cmds = append(cmds, cmd)
+	a.itemPanel, cmd = a.itemPanel.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return a, tea.Batch(cmds...)
+}
+
 func (a app) onSelectFeedItemMsg(msg message.SelectFeedItem) (app, tea.Cmd) {
 	var cmd tea.Cmd
 	a.previewPanel, cmd = a.previewPanel.Update(msg)
@@ -227,8 +404,8 @@ func (a app) onToogleReadMsg(msg message.ToogleRead) (app, tea.Cmd) {
 
 	if msg.IsSuccessful() {
 		var cmd tea.Cmd
-		a.feedPanel, cmd = a.feedPanel.Update(msg)
-		return a, cmd
+		a.itemPanel, cmd = a.itemPanel.Update(msg)
+		return a, tea.Batch(cmd, message.LoadCountsCmd(a.repo))
 	}
 
 	return a, nil
@@ -237,14 +414,14 @@ func (a app) onToogleReadMsg(msg message.ToogleRead) (app, tea.Cmd) {
 func (a app) onMarkAllReadMsg(msg message.MarkAllRead) (app, tea.Cmd) {
 	if msg.IsFailed() {
 		a.logger.Error("mark all read failed",
-			"item ids", msg.ItemIDs, "err", msg.Err)
+			"feed id", msg.Feed.ID, "err", msg.Err)
 		return a, nil
 	}
 
 	if msg.IsSuccessful() {
 		var cmd tea.Cmd
-		a.feedPanel, cmd = a.feedPanel.Update(msg)
-		return a, cmd
+		a.itemPanel, cmd = a.itemPanel.Update(msg)
+		return a, tea.Batch(cmd, message.LoadCountsCmd(a.repo))
 	}
 
 	return a, nil
@@ -259,28 +436,232 @@ func (a app) onToogleStarredMsg(msg message.ToogleStarred) (app, tea.Cmd) {
 
 	if msg.IsSuccessful() {
 		var cmd tea.Cmd
-		a.feedPanel, cmd = a.feedPanel.Update(msg)
+		a.itemPanel, cmd = a.itemPanel.Update(msg)
+		return a, tea.Batch(cmd, message.LoadCountsCmd(a.repo))
+	}
+
+	return a, nil
+}
+
+func (a app) onMarkUpdateSeenMsg(msg message.MarkUpdateSeen) (app, tea.Cmd) {
+	if msg.IsFailed() {
+		a.logger.Error("mark update seen failed",
+			"item id", msg.ItemID, "err", msg.Err)
+		return a, nil
+	}
+
+	if msg.IsSuccessful() {
+		var cmd tea.Cmd
+		a.itemPanel, cmd = a.itemPanel.Update(msg)
 		return a, cmd
 	}
 
 	return a, nil
 }
 
+func (a app) onToogleUpdatedUnreadMsg(msg message.ToogleUpdatedUnread) (app, tea.Cmd) {
+	if msg.IsFailed() {
+		return a, message.ErrTipsCmd("Toogle updated unread failed", msg.Err, true)
+	}
+
+	if !msg.IsSuccessful() {
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.feedPanel, cmd = a.feedPanel.Update(msg)
+
+	v := "Updated items of %s stay read"
+	if msg.Feed.MarkUpdatedUnread {
+		v = "Updated items of %s will be marked unread"
+	}
+	return a, tea.Batch(cmd, message.TipsCmd(fmt.Sprintf(v, msg.Feed.Name), true))
+}
+
+func (a app) onToogleFullContentMsg(msg message.ToogleFullContent) (app, tea.Cmd) {
+	if msg.IsFailed() {
+		return a, message.ErrTipsCmd("Toogle full article failed", msg.Err, true)
+	}
+
+	if !msg.IsSuccessful() {
+		return a, nil
+	}
+
+	var cmd tea.Cmd
+	a.feedPanel, cmd = a.feedPanel.Update(msg)
+
+	v := "Full articles of %s will not be extracted"
+	if msg.Feed.ExtractFullContent {
+		v = "Full articles of %s will be extracted on refresh"
+	}
+	return a, tea.Batch(cmd, message.TipsCmd(fmt.Sprintf(v, msg.Feed.Name), true))
+}
+
+// feedRequest is the request of the feed feedID. Smart feeds hold
+// copies of items, the request is on the feed.
+func (a app) feedRequest(feedID int64) rss.Request {
+	for _, f := range a.feedPanel.NormalFeeds() {
+		if f.ID == feedID {
+			return f.Request
+		}
+	}
+	return rss.Request{}
+}
+
+func (a app) onExtractFullContentMsg(msg message.ExtractFullContent) (app, tea.Cmd) {
+	switch {
+	case msg.IsInitial():
+		r := a.feedRequest(msg.FeedItem.FeedID)
+		return a, message.ExtractFullContentCmd(msg.FeedItem, r, a.repo, a.fetcher)
+	case msg.IsInProgress():
+		return a, message.TipsCmd("Extracting full article ...", false)
+	case msg.IsFailed():
+		return a, message.ErrTipsCmd("Extract full article failed", msg.Err, true)
+	}
+
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+	a.previewPanel, cmd = a.previewPanel.Update(msg)
+	cmds = append(cmds, cmd)
+	cmds = append(cmds, message.TipsCmd("Full article extracted", true))
+	return a, tea.Batch(cmds...)
+}
+
+func (a app) onBackfillMsg(msg message.Backfill) (app, tea.Cmd) {
+	switch {
+	case msg.IsInitial():
+		return a, message.BackfillCmd(msg.Feed, a.repo, a.fetcher, a.cfg.BackfillPages)
+	case msg.IsInProgress():
+		return a, message.TipsCmd(fmt.Sprintf("Backfilling history of %s ...", msg.Feed.Name), false)
+	case errors.Is(msg.Err, rss.ErrNoArchive):
+		return a, message.TipsCmd(fmt.Sprintf("%s has no archive pages", msg.Feed.Name), true)
+	case msg.IsFailed():
+		return a, message.ErrTipsCmd("Backfill failed", msg.Err, true)
+	}
+
+	cmd := a.feedPanel.UpdateFeed(msg.Feed, false)
+	v := fmt.Sprintf("%d older items of %s added", msg.Added, msg.Feed.Name)
+	return a, tea.Batch(cmd, message.TipsCmd(v, true))
+}
+
+// onParseMDMsg shows the item, the item panel marks its update seen
+// once the preview got what changed.
 func (a app) onParseMDMsg(msg message.ParseMD) (app, tea.Cmd) {
 	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
 	a.previewPanel, cmd = a.previewPanel.Update(msg)
-	return a, cmd
+	cmds = append(cmds, cmd)
+
+	a.itemPanel, cmd = a.itemPanel.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return a, tea.Batch(cmds...)
+}
+
+func (a app) onQueuePlayMsg(msg message.QueuePlay) (app, tea.Cmd) {
+	i := msg.FeedItem
+	e, ok := i.Media()
+	if !ok {
+		return a, message.TipsCmd("No audio or video to play", true)
+	}
+
+	// Play the downloaded file when there is one.
+	media := e.URL
+	if path, ok := a.manager.Path(e.ID); ok {
+		media = path
+	}
+
+	n, err := a.player.Enqueue(player.Track{FeedItem: i, Media: media})
+	switch {
+	case err != nil:
+		return a, message.ErrTipsCmd("Play failed", err, true)
+	case n < 0:
+		return a, message.TipsCmd("Already in play queue", true)
+	case n == 0:
+		return a, message.TipsCmd("Playing "+i.Title, true)
+	}
+	return a, message.TipsCmd(fmt.Sprintf("Queued, %d ahead in play queue", n), true)
+}
+
+func (a app) onPlayedMsg(msg message.Played) (app, tea.Cmd) {
+	var cmd tea.Cmd
+	cmds := []tea.Cmd{message.WaitPlayedCmd(a.player)}
+
+	if _, ok := a.dialog.(dialog.PlayQueue); ok {
+		a.dialog, cmd = a.dialog.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if msg.Err != nil {
+		cmds = append(cmds, message.ErrTipsCmd("Play failed", msg.Err, true))
+		return a, tea.Batch(cmds...)
+	}
+
+	// Quit or skipped tracks are not played yet.
+	if msg.Completed {
+		cmds = append(cmds, message.MarkPlayedCmd(msg.Track.FeedItem.ID, a.repo))
+	}
+	return a, tea.Batch(cmds...)
+}
+
+func (a app) onMarkPlayedMsg(msg message.MarkPlayed) (app, tea.Cmd) {
+	if msg.IsFailed() {
+		a.logger.Error("mark played failed",
+			"item id", msg.ItemID, "err", msg.Err)
+		return a, nil
+	}
+
+	if msg.IsSuccessful() {
+		var cmd tea.Cmd
+		a.itemPanel, cmd = a.itemPanel.Update(msg)
+		return a, cmd
+	}
+
+	return a, nil
+}
+
+func (a app) onQueueDownloadMsg(msg message.QueueDownload) (app, tea.Cmd) {
+	i := msg.FeedItem
+	if len(i.Enclosures) == 0 {
+		return a, message.TipsCmd("No attachments to download", true)
+	}
+
+	r := a.feedRequest(i.FeedID)
+	for _, e := range i.Enclosures {
+		a.manager.Add(e, i.Title, r)
+	}
+	v := fmt.Sprintf("%d attachments queued for download", len(i.Enclosures))
+	return a, message.TipsCmd(v, true)
+}
+
+func (a app) onDownloadsMsg(msg message.Downloads) (app, tea.Cmd) {
+	var cmd tea.Cmd
+	cmds := []tea.Cmd{message.WaitDownloadsCmd(a.manager)}
+
+	if _, ok := a.dialog.(dialog.Downloads); ok {
+		a.dialog, cmd = a.dialog.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return a, tea.Batch(cmds...)
 }
 
 func (a app) onRefreshMsg(msg message.Refresh) (app, tea.Cmd) {
 	a.refreshMsg = msg
 
+	if msg.IsCancelled() {
+		// Queued feeds are still due, the next tick refreshes them.
+		a.queuedFeeds = nil
+		return a, message.TipsCmd("Refresh cancelled", true)
+	}
+
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
 	if msg.IsInProgress() {
 		v := fmt.Sprintf("Refreshing %d of %d ...", len(msg.Results)+1, msg.Total)
 		cmds = append(cmds, message.TipsCmd(v, false))
+		cmds = append(cmds, msg.Next())
 	}
 
 	if len(msg.Results) == 0 {
@@ -289,35 +670,84 @@ func (a app) onRefreshMsg(msg message.Refresh) (app, tea.Cmd) {
 
 	ret := msg.Results[len(msg.Results)-1]
 	if ret.IsFailed() {
-		a.logger.Error("refresh failed", "feed id", ret.Feed.ID, "err", ret.Err)
+		a.logger.Error("refresh failed", "feed id", ret.Feed.ID,
+			"failures", ret.Feed.FailureCount, "dead", ret.Feed.IsDead, "err", ret.Err)
 	}
 
-	if ret.IsSuccessful() {
-		cmd = a.feedPanel.UpdateFeed(ret.Feed)
-		cmds = append(cmds, cmd)
+	if ret.MovedFrom != "" {
+		a.logger.Info("feed moved", "feed id", ret.Feed.ID,
+			"from", ret.MovedFrom, "to", ret.Feed.FeedURL)
+	}
+
+	if ret.Pruned > 0 {
+		a.logger.Info("items pruned", "feed id", ret.Feed.ID, "count", ret.Pruned)
 	}
 
-	if len(msg.Results) == msg.Total {
-		a.refreshMsg = message.NewRefreshSuccessful(msg.Total, msg.Results)
+	// Failed feeds are updated too, they carry the time to retry.
+	cmd = a.feedPanel.UpdateFeed(ret.Feed, ret.Pruned > 0)
+	cmds = append(cmds, cmd)
+
+	if msg.IsSuccessful() {
 		cmd = message.TipsCmd("Refresh finished", true)
 		cmds = append(cmds, cmd)
+
+		if len(a.queuedFeeds) > 0 {
+			cmds = append(cmds, a.refreshCmd(a.queuedFeeds))
+			a.queuedFeeds = nil
+		}
+
+		// Pruning leaves free pages behind, Optimize gives them back
+		// a few at a time.
+		cmd = func() tea.Msg {
+			if err := a.repo.Optimize(context.Background()); err != nil {
+				a.logger.Error("optimize database failed", "err", err)
+			}
+			return nil
+		}
+		cmds = append(cmds, cmd)
 	}
 
 	return a, tea.Batch(cmds...)
 }
 
-func (a app) onRefreshTickMsg(_ message.RefreshTick) (app, tea.Cmd) {
+func (a app) onRefreshTickMsg(msg message.RefreshTick) (app, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	cmd := message.RefreshTickCmd(a.cfg.RefreshInterval)
+	cmd := message.RefreshTickCmd(refreshTickInterval)
 	cmds = append(cmds, cmd)
 
-	cmd = message.RefreshCmd(a.feedPanel.NormalFeeds(), a.repo)
-	cmds = append(cmds, cmd)
+	// Skip this tick if the last refresh is still running.
+	if !a.refreshMsg.IsInProgress() {
+		cmd = a.refreshCmd(a.feedPanel.DueFeeds(time.Time(msg)))
+		cmds = append(cmds, cmd)
+	}
 
 	return a, tea.Batch(cmds...)
 }
 
+// onWatchChangesMsg reloads the feeds other processes changed, the
+// items are only reloaded if the selected feed is one of them.
+func (a app) onWatchChangesMsg(msg message.WatchChanges) (app, tea.Cmd) {
+	cmd := message.WatchChangesCmd(watchInterval, a.repo)
+
+	if msg.IsFailed() {
+		a.logger.Error("watch changes failed", "err", msg.Err)
+		return a, cmd
+	}
+	if len(msg.Changed) == 0 {
+		return a, cmd
+	}
+
+	a.logger.Info("reload feeds changed by another process", "feeds", len(msg.Changed))
+	reload := a.feedPanel.ReloadFeeds(msg.Changed, msg.Feeds)
+	return a, tea.Batch(cmd, reload)
+}
+
+func (a app) refreshCmd(feeds []rss.Feed) tea.Cmd {
+	return message.RefreshCmd(feeds, a.repo, a.fetcher, a.cfg.MaxRefresh, a.cfg.RefreshInterval,
+		rss.Retention(a.cfg.Retention))
+}
+
 func (a app) onExportMsg(msg message.Export) (app, tea.Cmd) {
 	if msg.IsInProgress() {
 		return a, message.TipsCmd("Exporting...", false)
@@ -350,8 +780,19 @@ func (a app) onImportMsg(msg message.Import) (app, tea.Cmd) {
 		cmd = a.feedPanel.AddFeeds(msg.Feeds)
 		cmds = append(cmds, cmd)
 
-		cmd = message.RefreshCmd(msg.Feeds, a.repo)
-		cmds = append(cmds, cmd)
+		// One refresh runs at a time, so cancelling stops all of it.
+		if a.refreshMsg.IsInProgress() {
+			a.queuedFeeds = append(a.queuedFeeds, msg.Feeds...)
+		} else {
+			cmd = a.refreshCmd(msg.Feeds)
+			cmds = append(cmds, cmd)
+		}
+
+		if len(msg.Skipped) > 0 {
+			a.logger.Warn("skip imported feeds not fetched over http", "urls", msg.Skipped)
+			v := fmt.Sprintf("Skipped %d command or local file feeds, add them by hand", len(msg.Skipped))
+			cmds = append(cmds, message.TipsCmd(v, true))
+		}
 
 		return a, tea.Batch(cmds...)
 	}
@@ -362,10 +803,18 @@ func (a app) onImportMsg(msg message.Import) (app, tea.Cmd) {
 
 func (a *app) onKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	if key.Matches(msg, a.cfg.KeyMap.Quit) {
+		// Stop refresh, so its writes finish before the store is closed.
+		a.refreshMsg.Cancel()
+		a.manager.Close()
+		a.player.Close()
 		return tea.Quit
 	}
 
 	if key.Matches(msg, a.cfg.KeyMap.Esc) {
+		// Esc closes dialog first, then cancels the running refresh.
+		if a.dialog == nil && a.refreshMsg.IsInProgress() {
+			a.refreshMsg.Cancel()
+		}
 		a.dialog = nil
 		a.statusBar.Hide()
 		a.setSizes()
@@ -409,7 +858,27 @@ func (a *app) onKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	}
 
 	if key.Matches(msg, a.cfg.KeyMap.AddFeed) {
-		a.dialog = dialog.NewAddFeed(a.cfg, a.repo)
+		a.dialog = dialog.NewAddFeed(a.cfg, a.repo, a.fetcher)
+		return a.dialog.Init()
+	}
+
+	if key.Matches(msg, a.cfg.KeyMap.ScrapedFeed) {
+		a.dialog = dialog.NewAddScrapedFeed(a.cfg, a.repo, a.fetcher)
+		return a.dialog.Init()
+	}
+
+	if key.Matches(msg, a.cfg.KeyMap.Search) {
+		a.dialog = dialog.NewSearch(a.cfg, a.repo)
+		return a.dialog.Init()
+	}
+
+	if key.Matches(msg, a.cfg.KeyMap.PlayQueue) {
+		a.dialog = dialog.NewPlayQueue(a.cfg, a.player)
+		return a.dialog.Init()
+	}
+
+	if key.Matches(msg, a.cfg.KeyMap.Downloads) {
+		a.dialog = dialog.NewDownloads(a.cfg, a.manager)
 		return a.dialog.Init()
 	}
 
@@ -447,7 +916,7 @@ func (a *app) onRefreshKeyMsg() tea.Cmd {
 		return nil
 	}
 
-	return message.RefreshCmd(feeds, a.repo)
+	return a.refreshCmd(feeds)
 }
 
 func (a *app) onExportKeyMsg() tea.Cmd {
@@ -11,10 +11,34 @@ type App struct {
 	FeedPanelWidth  int
 	ItemPanelWidth  int
 	RefreshInterval time.Duration
+	MaxRefresh      int
+	BackfillPages   int
+	Player          string
+	HTTP            HTTP
+	Download        Download
+	Retention       Retention
 	Theme           *AppTheme
 	KeyMap          *keyMap
 }
 
+type HTTP struct {
+	UserAgent   string
+	Proxy       string
+	Timeout     time.Duration
+	MaxBodySize int64
+}
+
+type Download struct {
+	Dir           string
+	MaxConcurrent int
+}
+
+// Retention of items, zero keeps all.
+type Retention struct {
+	KeepItems    int
+	KeepReadDays int
+}
+
 type keyMap struct {
 	Up            key.Binding
 	Down          key.Binding
@@ -25,12 +49,24 @@ type keyMap struct {
 	PrevFocus     key.Binding
 	NextFocus     key.Binding
 	AddFeed       key.Binding
+	ScrapedFeed   key.Binding
 	DeleteFeed    key.Binding
 	ToogleStarred key.Binding
 	ToogleRead    key.Binding
 	MarkAllRead   key.Binding
+	UpdatedUnread key.Binding
 	RenameFeed    key.Binding
+	FeedRequest   key.Binding
+	FeedFilter    key.Binding
+	FeedRetention key.Binding
+	FullContent   key.Binding
+	Backfill      key.Binding
+	Play          key.Binding
+	PlayQueue     key.Binding
+	Download      key.Binding
+	Downloads     key.Binding
 	Refresh       key.Binding
+	Search        key.Binding
 	Open          key.Binding
 	Export        key.Binding
 	Import        key.Binding
@@ -48,8 +84,8 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.PrevPage, k.NextPage, k.Start, k.End, k.PrevFocus, k.NextFocus},
-		{k.AddFeed, k.DeleteFeed, k.RenameFeed, k.ToogleStarred, k.ToogleRead, k.MarkAllRead, k.Refresh},
-		{k.Open, k.Export, k.Import},
+		{k.AddFeed, k.ScrapedFeed, k.DeleteFeed, k.RenameFeed, k.ToogleStarred, k.ToogleRead, k.MarkAllRead, k.UpdatedUnread, k.Refresh, k.Search},
+		{k.Open, k.FeedRequest, k.FeedFilter, k.FeedRetention, k.FullContent, k.Backfill, k.Play, k.PlayQueue, k.Download, k.Downloads, k.Export, k.Import},
 		{k.Enter, k.Esc, k.OpenDir, k.Help, k.Quit},
 	}
 }
@@ -61,6 +97,7 @@ type AppTheme struct {
 	BorderActive            lipgloss.Color
 	Starred                 lipgloss.Color
 	Unread                  lipgloss.Color
+	Updated                 lipgloss.Color
 	Error                   lipgloss.Color
 	CancelButton            lipgloss.Color
 	CancelButtonBackground  lipgloss.Color
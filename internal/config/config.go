@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"dario.cat/mergo"
@@ -105,20 +106,75 @@ func loadX[T any](dir string, embedFS embed.FS, filename string) (*T, error) {
 }
 
 type config struct {
-	ThemeName       string  `toml:"theme" comment:"Theme name"` //nolint:golines
-	FeedPanelWidth  int     `toml:"feed_panel_width" comment:"\nWidth of feed panel"`
-	ItemPanelWidth  int     `toml:"item_panel_width" comment:"\nWidth of item panel"`
-	RefreshInterval int     `toml:"refresh_interval" comment:"\nAuto refresh interval in minutes"`
-	Hotkey          *hotkey `toml:"-"`
-	Theme           *theme  `toml:"-"`
+	ThemeName       string          `toml:"theme" comment:"Theme name"` //nolint:golines
+	FeedPanelWidth  int             `toml:"feed_panel_width" comment:"\nWidth of feed panel"`
+	ItemPanelWidth  int             `toml:"item_panel_width" comment:"\nWidth of item panel"`
+	RefreshInterval int             `toml:"refresh_interval" comment:"\nAuto refresh interval in minutes, feeds that rarely publish are refreshed less often"`
+	MaxRefresh      int             `toml:"max_concurrent_refresh" comment:"\nMax feeds refreshed at the same time"`
+	BackfillPages   int             `toml:"backfill_pages" comment:"\nMax archive pages fetched when backfilling history of a feed"`
+	Player          string          `toml:"player" comment:"\nCommand playing audio and video of items, {url} is replaced with the URL or the downloaded file. Items are marked played when the player reports the end of the file, like mpv does"`
+	HTTP            httpConfig      `toml:"http" comment:"\nHTTP client"`
+	Download        downloadConfig  `toml:"download" comment:"\nDownloads of podcasts and other attachments"`
+	Retention       retentionConfig `toml:"retention" comment:"\nRetention of items, feeds can override it, starred items are always kept"`
+	Hotkey          *hotkey         `toml:"-"`
+	Theme           *theme          `toml:"-"`
+}
+
+type httpConfig struct {
+	UserAgent   string `toml:"user_agent" comment:"User agent, empty for the default of RssX"`
+	Proxy       string `toml:"proxy" comment:"\nHTTP or SOCKS5 proxy, e.g. socks5://127.0.0.1:1080, empty for the proxy of environment"`
+	Timeout     int    `toml:"timeout" comment:"\nRequest timeout in seconds"`
+	MaxBodySize int    `toml:"max_body_size" comment:"\nMax size of a downloaded feed in MB"`
+}
+
+type downloadConfig struct {
+	Dir           string `toml:"dir" comment:"Directory attachments are downloaded to, empty for Downloads/RssX in home dir"`
+	MaxConcurrent int    `toml:"max_concurrent" comment:"\nMax downloads running at the same time"`
+}
+
+type retentionConfig struct {
+	KeepItems    int `toml:"keep_items" comment:"Keep the latest N items of each feed, 0 keeps all"`
+	KeepReadDays int `toml:"keep_read_days" comment:"\nDrop read items older than N days, 0 keeps all"`
 }
 
 func (c config) toApp() *App {
 	return &App{
 		RefreshInterval: time.Duration(c.RefreshInterval) * time.Minute,
-		FeedPanelWidth:  c.FeedPanelWidth,
-		ItemPanelWidth:  c.ItemPanelWidth,
-		Theme:           c.Theme.toApp(),
-		KeyMap:          c.Hotkey.toApp(),
+		MaxRefresh:      c.MaxRefresh,
+		BackfillPages:   c.BackfillPages,
+		Player:          c.Player,
+		HTTP: HTTP{
+			UserAgent:   c.HTTP.UserAgent,
+			Proxy:       c.HTTP.Proxy,
+			Timeout:     time.Duration(c.HTTP.Timeout) * time.Second,
+			MaxBodySize: int64(c.HTTP.MaxBodySize) << 20, //nolint:mnd // MB
+		},
+		Download: Download{
+			Dir:           downloadDir(c.Download.Dir),
+			MaxConcurrent: c.Download.MaxConcurrent,
+		},
+		Retention: Retention{
+			KeepItems:    c.Retention.KeepItems,
+			KeepReadDays: c.Retention.KeepReadDays,
+		},
+		FeedPanelWidth: c.FeedPanelWidth,
+		ItemPanelWidth: c.ItemPanelWidth,
+		Theme:          c.Theme.toApp(),
+		KeyMap:         c.Hotkey.toApp(),
+	}
+}
+
+// downloadDir expands ~ in dir, an empty dir is Downloads/RssX in home.
+func downloadDir(dir string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return dir
+	}
+	if dir == "" {
+		return filepath.Join(home, "Downloads", "RssX")
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		return filepath.Join(home, dir[1:])
 	}
+	return dir
 }
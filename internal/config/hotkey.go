@@ -17,12 +17,24 @@ type hotkey struct {
 	NextFocus []string `toml:"next_focus" comment:"Focus on next panel"`
 
 	AddFeed       []string `toml:"add_feed" comment:"\nAdd feed"` //nolint:golines
+	ScrapedFeed   []string `toml:"add_scraped_feed" comment:"Add feed scraped from a web page with CSS selectors"`
 	DeleteFeed    []string `toml:"delete_feed" comment:"Delete feed"`
 	RenameFeed    []string `toml:"rename_feed" comment:"Rename feed"`
 	ToogleStarred []string `toml:"toogle_starred" comment:"Toogle starred status"`
 	ToogleRead    []string `toml:"toogle_read" comment:"Toogle read status"` //nolint:golines
 	MarkAllRead   []string `toml:"mark_all_read" comment:"Mark all items as read"`
+	UpdatedUnread []string `toml:"toogle_updated_unread" comment:"Toogle marking updated items of feed as unread"`
+	FeedRequest   []string `toml:"feed_request" comment:"Edit headers, cookie and auth of feed requests"`
+	FeedFilter    []string `toml:"feed_filter" comment:"Edit command feeds are piped through before parsing"`
+	FeedRetention []string `toml:"feed_retention" comment:"Edit how many items of feed are kept"`
+	FullContent   []string `toml:"full_content" comment:"Extract full article of item, or toogle extracting it on refresh of feed"`
+	Backfill      []string `toml:"backfill" comment:"Backfill history of feed from its archive pages"`
+	Play          []string `toml:"play" comment:"Play audio or video of item, or queue it when playing"`
+	PlayQueue     []string `toml:"play_queue" comment:"Show play queue, to skip tracks or stop playing"`
+	Download      []string `toml:"download" comment:"Download attachments of item, e.g. podcast episodes"`
+	Downloads     []string `toml:"downloads" comment:"Show downloads"`
 	Refresh       []string `toml:"refresh" comment:"Refresh feeds"`
+	Search        []string `toml:"search" comment:"Search all articles"`
 
 	Open   []string `toml:"open" comment:"\nOpen in browser"`
 	Export []string `toml:"export" comment:"Export OPML"`
@@ -46,12 +58,24 @@ func (h hotkey) toApp() *keyMap {
 		PrevFocus:     newBinding(h.PrevFocus, "prev focus"),
 		NextFocus:     newBinding(h.NextFocus, "next focus"),
 		AddFeed:       newBinding(h.AddFeed, "add feed"),
+		ScrapedFeed:   newBinding(h.ScrapedFeed, "add scraped feed"),
 		DeleteFeed:    newBinding(h.DeleteFeed, "delete feed"),
 		ToogleStarred: newBinding(h.ToogleStarred, "toogle starred"),
 		ToogleRead:    newBinding(h.ToogleRead, "toogle read"),
 		MarkAllRead:   newBinding(h.MarkAllRead, "mark all items read"),
+		UpdatedUnread: newBinding(h.UpdatedUnread, "toogle updated unread"),
 		RenameFeed:    newBinding(h.RenameFeed, "rename feed"),
+		FeedRequest:   newBinding(h.FeedRequest, "edit feed request"),
+		FeedFilter:    newBinding(h.FeedFilter, "edit feed filter"),
+		FeedRetention: newBinding(h.FeedRetention, "edit feed retention"),
+		FullContent:   newBinding(h.FullContent, "full article"),
+		Backfill:      newBinding(h.Backfill, "backfill history"),
+		Play:          newBinding(h.Play, "play media"),
+		PlayQueue:     newBinding(h.PlayQueue, "show play queue"),
+		Download:      newBinding(h.Download, "download attachments"),
+		Downloads:     newBinding(h.Downloads, "show downloads"),
 		Refresh:       newBinding(h.Refresh, "refresh feed"),
+		Search:        newBinding(h.Search, "search articles"),
 		Open:          newBinding(h.Open, "open in browser"),
 		Export:        newBinding(h.Export, "export OPML"),
 		Import:        newBinding(h.Import, "import OPML"),
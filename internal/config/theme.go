@@ -22,6 +22,7 @@ type theme struct {
 
 	Starred string `toml:"starred" comment:"\nStarred Feed Item"` //nolint:golines
 	Unread  string `toml:"unread" comment:"Unread Feed Item"`
+	Updated string `toml:"updated" comment:"Updated Feed Item"`
 
 	TextInput            string `toml:"text_input" comment:"\nText Input"`
 	TextInputPlaceholder string `toml:"text_input_placeholder"`
@@ -54,6 +55,7 @@ func (t theme) toApp() *AppTheme {
 		BorderActive:            lipgloss.Color(t.BorderActive),
 		Starred:                 lipgloss.Color(t.Starred),
 		Unread:                  lipgloss.Color(t.Unread),
+		Updated:                 lipgloss.Color(t.Updated),
 		Error:                   lipgloss.Color(t.Error),
 		CancelButton:            lipgloss.Color(t.CancelButton),
 		CancelButtonBackground:  lipgloss.Color(t.CancelButtonBackground),
@@ -0,0 +1,437 @@
+package download
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// partSuffix marks a file which is not fully downloaded yet, its size
+// is where the download resumes.
+const partSuffix = ".part"
+
+// notifyInterval limits how often progress is reported.
+const notifyInterval = 200 * time.Millisecond
+
+// Download is an enclosure in the download queue.
+type Download struct {
+	Enclosure rss.Enclosure
+	// Title is the title of the item of the enclosure.
+	Title string
+	// Size is how many bytes are downloaded.
+	Size int64
+	// Total is the size of the file, -1 when unknown.
+	Total int64
+	Err   error
+	// request is the request of the feed of the item, for enclosures
+	// behind a login.
+	request rss.Request
+}
+
+// State is the state of the download.
+func (d Download) State() rss.DownloadState {
+	return d.Enclosure.DownloadState
+}
+
+// Manager downloads enclosures into a directory with at most max
+// downloads running. The state of downloads is saved, so the queue
+// is restored on start and paused downloads resume where they stopped.
+type Manager struct {
+	dir     string
+	max     int
+	fetcher *rss.Fetcher
+	repo    rss.Repo
+	logger  *slog.Logger
+
+	mu        sync.Mutex
+	downloads []*Download
+	// jobs are the running downloads by enclosure id, a paused one
+	// stays until its run returned.
+	jobs    map[int64]*job
+	closed  bool
+	updates chan struct{}
+}
+
+func NewManager(dir string, maxConcurrent int, fetcher *rss.Fetcher,
+	repo rss.Repo, logger *slog.Logger) *Manager {
+	return &Manager{
+		dir:     dir,
+		max:     max(maxConcurrent, 1),
+		fetcher: fetcher,
+		repo:    repo,
+		logger:  logger,
+		jobs:    make(map[int64]*job),
+		updates: make(chan struct{}, 1),
+	}
+}
+
+// Updates is signaled whenever downloads change.
+func (m *Manager) Updates() <-chan struct{} {
+	return m.updates
+}
+
+// Downloads returns a copy of the downloads in queue order.
+func (m *Manager) Downloads() []Download {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	downloads := make([]Download, 0, len(m.downloads))
+	for _, d := range m.downloads {
+		downloads = append(downloads, *d)
+	}
+	return downloads
+}
+
+// Restore queues the downloads saved in items again, feeds are the
+// feeds of items. Downloads which were running when the app quit are
+// queued.
+func (m *Manager) Restore(items []rss.FeedItem, feeds []rss.Feed) {
+	requests := make(map[int64]rss.Request, len(feeds))
+	for _, f := range feeds {
+		requests[f.ID] = f.Request
+	}
+
+	m.mu.Lock()
+	for _, i := range items {
+		for _, e := range i.Enclosures {
+			if e.DownloadState == rss.DownloadNone || m.find(e.ID) != nil {
+				continue
+			}
+			if e.DownloadState == rss.DownloadRunning {
+				e.DownloadState = rss.DownloadQueued
+			}
+			d := &Download{Enclosure: e, Title: i.Title, Total: -1, request: requests[i.FeedID]}
+			d.Size = fileSize(e.Path, e.DownloadState)
+			m.downloads = append(m.downloads, d)
+		}
+	}
+	slices.SortFunc(m.downloads, func(a, b *Download) int {
+		return int(a.Enclosure.ID - b.Enclosure.ID)
+	})
+	m.mu.Unlock()
+
+	m.schedule()
+	m.notify()
+}
+
+// Add queues e for download, title is the title of its item and r
+// the request of its feed. Enclosures already in the queue are left
+// as they are.
+func (m *Manager) Add(e rss.Enclosure, title string, r rss.Request) {
+	m.mu.Lock()
+	if m.find(e.ID) != nil {
+		m.mu.Unlock()
+		return
+	}
+
+	e.DownloadState = rss.DownloadQueued
+	e.Path = filepath.Join(m.dir, fileName(e))
+	m.downloads = append(m.downloads, &Download{Enclosure: e, Title: title, Total: -1, request: r})
+	m.mu.Unlock()
+
+	m.save(e)
+	m.schedule()
+	m.notify()
+}
+
+// Toogle pauses a queued or running download, and queues a paused
+// or failed one again.
+func (m *Manager) Toogle(enclosureID int64) {
+	m.mu.Lock()
+	d := m.find(enclosureID)
+	if d == nil {
+		m.mu.Unlock()
+		return
+	}
+
+	switch d.State() {
+	case rss.DownloadQueued, rss.DownloadRunning:
+		d.Enclosure.DownloadState = rss.DownloadPaused
+		if j, ok := m.jobs[enclosureID]; ok {
+			j.cancel()
+		}
+	case rss.DownloadPaused, rss.DownloadFailed:
+		d.Enclosure.DownloadState = rss.DownloadQueued
+		d.Err = nil
+	default:
+		m.mu.Unlock()
+		return
+	}
+	e := d.Enclosure
+	m.mu.Unlock()
+
+	m.save(e)
+	m.schedule()
+	m.notify()
+}
+
+// Remove drops a download from the queue. The part of an unfinished
+// download is deleted, a finished file is kept.
+func (m *Manager) Remove(enclosureID int64) {
+	m.mu.Lock()
+	idx := slices.IndexFunc(m.downloads, func(d *Download) bool {
+		return d.Enclosure.ID == enclosureID
+	})
+	if idx < 0 {
+		m.mu.Unlock()
+		return
+	}
+
+	d := m.downloads[idx]
+	m.downloads = slices.Delete(m.downloads, idx, idx+1)
+	if j, ok := m.jobs[enclosureID]; ok {
+		j.cancel()
+	}
+	e := d.Enclosure
+	m.mu.Unlock()
+
+	if e.DownloadState != rss.DownloadDone {
+		// The download may still be writing, it stops soon after cancel.
+		if err := os.Remove(e.Path + partSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			m.logger.Error("remove part of download failed", "path", e.Path, "err", err)
+		}
+		e.Path = ""
+	}
+	e.DownloadState = rss.DownloadNone
+	m.save(e)
+	m.schedule()
+	m.notify()
+}
+
+// Path returns where the enclosure is downloaded to, if it is.
+func (m *Manager) Path(enclosureID int64) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d := m.find(enclosureID)
+	if d == nil || d.State() != rss.DownloadDone {
+		return "", false
+	}
+	return d.Enclosure.Path, true
+}
+
+// Close stops running downloads, they are resumed on next start.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	for _, j := range m.jobs {
+		j.cancel()
+	}
+}
+
+// schedule starts queued downloads while there are free slots.
+func (m *Manager) schedule() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	for _, d := range m.downloads {
+		if len(m.jobs) >= m.max {
+			return
+		}
+		// A download resumed right after pause waits for its last run,
+		// both would write the same part.
+		if _, ok := m.jobs[d.Enclosure.ID]; ok || d.State() != rss.DownloadQueued {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		j := &job{cancel: cancel}
+		m.jobs[d.Enclosure.ID] = j
+		d.Enclosure.DownloadState = rss.DownloadRunning
+		go m.run(ctx, d, j)
+	}
+}
+
+// job is a run of a download.
+type job struct {
+	cancel context.CancelFunc
+}
+
+func (m *Manager) run(ctx context.Context, d *Download, j *job) {
+	defer j.cancel()
+
+	m.mu.Lock()
+	e := d.Enclosure
+	m.mu.Unlock()
+	m.save(e)
+
+	err := m.download(ctx, d)
+
+	m.mu.Lock()
+	if m.jobs[e.ID] == j {
+		delete(m.jobs, e.ID)
+	}
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	// Paused and removed downloads are saved by Toogle and Remove.
+	if ctx.Err() == nil {
+		if err != nil {
+			d.Enclosure.DownloadState = rss.DownloadFailed
+			d.Err = err
+			m.logger.Error("download failed", "url", e.URL, "err", err)
+		} else {
+			d.Enclosure.DownloadState = rss.DownloadDone
+		}
+	}
+	e = d.Enclosure
+	m.mu.Unlock()
+
+	if ctx.Err() == nil {
+		m.save(e)
+	}
+	m.schedule()
+	m.notify()
+}
+
+// download writes the enclosure of d into its part file, resuming from
+// the size of the part, then moves the part to the path of d.
+func (m *Manager) download(ctx context.Context, d *Download) error {
+	m.mu.Lock()
+	e := d.Enclosure
+	r := d.request
+	m.mu.Unlock()
+
+	part := e.Path + partSuffix
+	if err := os.MkdirAll(filepath.Dir(part), 0o750); err != nil {
+		return err
+	}
+
+	var offset int64
+	if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+	}
+
+	t, err := m.fetcher.Transfer(ctx, e.URL, offset, r)
+	// A body starting past the part would leave a gap, start over.
+	if err == nil && t.Offset > offset {
+		t.Body.Close()
+		t, err = m.fetcher.Transfer(ctx, e.URL, 0, r)
+	}
+	if err != nil {
+		return err
+	}
+	defer t.Body.Close()
+	if t.Offset > offset {
+		return fmt.Errorf("server resumed at %d instead of %d", t.Offset, offset)
+	}
+
+	// The server may resume before the end of the part, the part is cut
+	// to where the body starts.
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err = f.Truncate(t.Offset); err != nil {
+		return err
+	}
+	if _, err = f.Seek(t.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	d.Size = t.Offset
+	d.Total = t.Total
+	m.mu.Unlock()
+	m.notify()
+
+	if err = m.copy(f, t.Body, d); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(part, e.Path)
+}
+
+func (m *Manager) copy(w io.Writer, r io.Reader, d *Download) error {
+	buf := make([]byte, 32<<10) //nolint:mnd // 32 KB
+	last := time.Now()
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			m.mu.Lock()
+			d.Size += int64(n)
+			m.mu.Unlock()
+
+			if time.Since(last) >= notifyInterval {
+				last = time.Now()
+				m.notify()
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (m *Manager) save(e rss.Enclosure) {
+	err := m.repo.UpdateDownload(context.Background(), e.ID, e.DownloadState, e.Path)
+	if err != nil {
+		m.logger.Error("save download failed", "id", e.ID, "err", err)
+	}
+}
+
+// notify never blocks, pending updates are merged into one.
+func (m *Manager) notify() {
+	select {
+	case m.updates <- struct{}{}:
+	default:
+	}
+}
+
+// find must be called with mu held.
+func (m *Manager) find(enclosureID int64) *Download {
+	for _, d := range m.downloads {
+		if d.Enclosure.ID == enclosureID {
+			return d
+		}
+	}
+	return nil
+}
+
+// fileName keeps enclosures of the same name apart by their id.
+func fileName(e rss.Enclosure) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, e.Name())
+	return fmt.Sprintf("%d-%s", e.ID, name)
+}
+
+// fileSize is how much of a download is on disk.
+func fileSize(path string, state rss.DownloadState) int64 {
+	if state != rss.DownloadDone {
+		path += partSuffix
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,213 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	rss.Repo
+	mu     sync.Mutex
+	states map[int64]rss.DownloadState
+}
+
+func (r *fakeRepo) UpdateDownload(_ context.Context, id int64, state rss.DownloadState, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[id] = state
+	return nil
+}
+
+func (r *fakeRepo) state(id int64) rss.DownloadState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.states[id]
+}
+
+func newTestManager(t *testing.T, dir string, repo rss.Repo) *Manager {
+	t.Helper()
+	f, err := rss.NewFetcher(rss.FetcherOptions{})
+	require.NoError(t, err)
+	m := NewManager(dir, 2, f, repo, slog.New(slog.DiscardHandler))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func TestManager(t *testing.T) {
+	content := []byte("0123456789")
+	var mu sync.Mutex
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		http.ServeContent(w, r, "episode.mp3", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	repo := &fakeRepo{states: map[int64]rss.DownloadState{}}
+	m := newTestManager(t, dir, repo)
+
+	// A part of the file is there from the last run.
+	e := rss.Enclosure{ID: 1, URL: srv.URL + "/episode.mp3", DownloadState: rss.DownloadRunning}
+	e.Path = filepath.Join(dir, fileName(e))
+	require.NoError(t, os.WriteFile(e.Path+partSuffix, content[:4], 0o600))
+
+	m.Restore([]rss.FeedItem{{Title: "Episode", Enclosures: []rss.Enclosure{e}}}, nil)
+	require.Eventually(t, func() bool {
+		return repo.state(1) == rss.DownloadDone
+	}, 5*time.Second, 10*time.Millisecond)
+
+	b, err := os.ReadFile(e.Path)
+	require.NoError(t, err)
+	assert.Equal(t, content, b)
+	assert.Equal(t, []string{"bytes=4-"}, ranges)
+
+	path, ok := m.Path(1)
+	assert.True(t, ok)
+	assert.Equal(t, e.Path, path)
+
+	downloads := m.Downloads()
+	require.Len(t, downloads, 1)
+	assert.Equal(t, "Episode", downloads[0].Title)
+	assert.Equal(t, int64(len(content)), downloads[0].Size)
+
+	m.Remove(1)
+	assert.Empty(t, m.Downloads())
+	assert.Equal(t, rss.DownloadNone, repo.state(1))
+	assert.FileExists(t, e.Path)
+}
+
+func TestManagerFailed(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	repo := &fakeRepo{states: map[int64]rss.DownloadState{}}
+	m := newTestManager(t, t.TempDir(), repo)
+
+	m.Add(rss.Enclosure{ID: 1, URL: srv.URL + "/episode.mp3"}, "Episode", rss.Request{})
+	require.Eventually(t, func() bool {
+		return repo.state(1) == rss.DownloadFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	downloads := m.Downloads()
+	require.Len(t, downloads, 1)
+	require.Error(t, downloads[0].Err)
+
+	// Failed downloads are retried.
+	m.Toogle(1)
+	assert.NotEqual(t, rss.DownloadFailed, m.Downloads()[0].State())
+	require.Eventually(t, func() bool {
+		return m.Downloads()[0].State() == rss.DownloadFailed
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManagerResumeElsewhere(t *testing.T) {
+	content := []byte("0123456789")
+	var mu sync.Mutex
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		if user, pass, ok := r.BasicAuth(); !ok || user != "u" || pass != "p" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Resume two bytes before the asked range, or past it.
+		switch r.Header.Get("Range") {
+		case "bytes=4-":
+			w.Header().Set("Content-Range", "bytes 2-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content[2:])
+		case "bytes=3-":
+			w.Header().Set("Content-Range", "bytes 6-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content[6:])
+		default:
+			_, _ = w.Write(content)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	repo := &fakeRepo{states: map[int64]rss.DownloadState{}}
+	m := newTestManager(t, dir, repo)
+	r := rss.Request{Username: "u", Password: "p"}
+
+	for i, part := range [][]byte{[]byte("01xx"), content[:3]} {
+		id := int64(i + 1)
+		e := rss.Enclosure{ID: id, ItemID: id, URL: srv.URL + "/episode.mp3", DownloadState: rss.DownloadQueued}
+		e.Path = filepath.Join(dir, fileName(e))
+		require.NoError(t, os.WriteFile(e.Path+partSuffix, part, 0o600))
+
+		items := []rss.FeedItem{{FeedID: 9, Title: "Episode", Enclosures: []rss.Enclosure{e}}}
+		m.Restore(items, []rss.Feed{{ID: 9, Request: r}})
+		require.Eventually(t, func() bool {
+			return repo.state(id) == rss.DownloadDone
+		}, 5*time.Second, 10*time.Millisecond)
+
+		b, err := os.ReadFile(e.Path)
+		require.NoError(t, err)
+		assert.Equal(t, content, b)
+	}
+	assert.Equal(t, []string{"bytes=4-", "bytes=3-", ""}, ranges)
+}
+
+func TestManagerResumeBeforeStopped(t *testing.T) {
+	var active atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		active.Add(1)
+		defer active.Add(-1)
+		w.Header().Set("Content-Length", "10")
+		_, _ = w.Write([]byte("01"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	repo := &fakeRepo{states: map[int64]rss.DownloadState{}}
+	m := newTestManager(t, t.TempDir(), repo)
+
+	m.Add(rss.Enclosure{ID: 1, URL: srv.URL + "/episode.mp3"}, "Episode", rss.Request{})
+	require.Eventually(t, func() bool {
+		return active.Load() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Resume before the paused run returned, it runs once again.
+	m.Toogle(1)
+	m.Toogle(1)
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.jobs) == 1 && m.downloads[0].State() == rss.DownloadRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// It can still be paused.
+	m.Toogle(1)
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.jobs) == 0 && active.Load() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, rss.DownloadPaused, repo.state(1))
+}
+
+func TestFileName(t *testing.T) {
+	e := rss.Enclosure{ID: 7, URL: "https://example.com/a/ep:1*.mp3?x=1"}
+	assert.Equal(t, "7-ep_1_.mp3", fileName(e))
+}
@@ -1,12 +1,19 @@
 package message
 
 import (
+	"context"
+	"errors"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/mmcdole/gofeed"
 )
 
-func AddFeedCmd(v string, repo rss.Repo) tea.Cmd {
+// AddFeedCmd adds the feed at f.FeedURL, the request of f is used to
+// fetch it and saved with the feed.
+func AddFeedCmd(f rss.Feed, repo rss.Repo, fetcher *rss.Fetcher) tea.Cmd {
 	var cmds []tea.Cmd
+	v := f.FeedURL
 
 	cmd := func() tea.Msg {
 		return NewAddFeedInProgress(v)
@@ -14,7 +21,20 @@ func AddFeedCmd(v string, repo rss.Repo) tea.Cmd {
 	cmds = append(cmds, cmd)
 
 	cmd = func() tea.Msg {
-		feed, err := rss.ParseURL(v)
+		feed, err := fetcher.Fetch(context.Background(), f)
+		if errors.Is(err, gofeed.ErrFeedTypeNotDetected) && rss.IsHTTPURL(v) {
+			// Not a feed, maybe the home page of a site.
+			var links []rss.FeedLink
+			links, err = fetcher.Discover(context.Background(), v, f.Request)
+			if err != nil {
+				return NewAddFeedFailed(v, err)
+			}
+			if len(links) > 1 {
+				return NewDiscoverFeedsSuccessful(f, links)
+			}
+			f.FeedURL = links[0].URL
+			feed, err = fetcher.Fetch(context.Background(), f)
+		}
 		if err != nil {
 			return NewAddFeedFailed(v, err)
 		}
@@ -0,0 +1,80 @@
+package message
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// BackfillCmd stores the older items of f from its archive pages,
+// at most maxPages older pages are fetched.
+func BackfillCmd(f rss.Feed, repo rss.Repo, fetcher *rss.Fetcher, maxPages int) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewBackfillInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		ctx := context.Background()
+		added := 0
+		err := fetcher.Backfill(ctx, f, maxPages, func(items []rss.FeedItem) error {
+			// Store skips items it already has.
+			saved, err := repo.InsertItems(ctx, f.ID, items)
+			if err != nil {
+				return err
+			}
+			f.Merge(saved)
+			added += len(saved)
+			return nil
+		})
+		// Items of the pages before a failure are kept.
+		if err != nil && added == 0 {
+			return NewBackfillFailed(f, err)
+		}
+		return NewBackfillSuccessful(f, added)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type Backfill struct {
+	Feed rss.Feed
+	// Added is how many older items were stored.
+	Added int
+	status
+	Err error
+}
+
+func NewBackfillInitial(f rss.Feed) Backfill {
+	return Backfill{
+		Feed:   f,
+		status: statusInitial,
+	}
+}
+
+func NewBackfillInProgress(f rss.Feed) Backfill {
+	return Backfill{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewBackfillSuccessful(f rss.Feed, added int) Backfill {
+	return Backfill{
+		Feed:   f,
+		Added:  added,
+		status: statusSuccessful,
+	}
+}
+
+func NewBackfillFailed(f rss.Feed, err error) Backfill {
+	return Backfill{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
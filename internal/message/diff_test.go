@@ -0,0 +1,15 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	old := "a\nb\nc\nd"
+	cur := "a\nc\nd\ne"
+
+	assert.Equal(t, []string{"- b", "+ e"}, diffLines(old, cur))
+	assert.Empty(t, diffLines(old, old))
+}
@@ -0,0 +1,22 @@
+package message
+
+import (
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// DiscoverFeeds is sent by AddFeedCmd when the page links to several
+// feeds, so the user can pick the one to add.
+// Feed is the feed being added, the picked link replaces its URL.
+type DiscoverFeeds struct {
+	Feed  rss.Feed
+	Links []rss.FeedLink
+	status
+}
+
+func NewDiscoverFeedsSuccessful(f rss.Feed, links []rss.FeedLink) DiscoverFeeds {
+	return DiscoverFeeds{
+		Feed:   f,
+		Links:  links,
+		status: statusSuccessful,
+	}
+}
@@ -0,0 +1,29 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/download"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// QueueDownload asks to download the enclosures of the item.
+type QueueDownload struct {
+	FeedItem rss.FeedItem
+}
+
+func NewQueueDownload(i rss.FeedItem) QueueDownload {
+	return QueueDownload{FeedItem: i}
+}
+
+// WaitDownloadsCmd waits for the next change of downloads of m,
+// call it again after each Downloads message to keep watching.
+func WaitDownloadsCmd(m *download.Manager) tea.Cmd {
+	return func() tea.Msg {
+		<-m.Updates()
+		return Downloads{Downloads: m.Downloads()}
+	}
+}
+
+type Downloads struct {
+	Downloads []download.Download
+}
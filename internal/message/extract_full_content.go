@@ -0,0 +1,68 @@
+package message
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func ExtractFullContentCmd(i rss.FeedItem, r rss.Request, repo rss.Repo, fetcher *rss.Fetcher) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewExtractFullContentInProgress(i)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		ctx := context.Background()
+		v, err := fetcher.Extract(ctx, i.Link, r)
+		if err != nil {
+			return NewExtractFullContentFailed(i, err)
+		}
+		if err = repo.SetFullContent(ctx, i.ID, v); err != nil {
+			return NewExtractFullContentFailed(i, err)
+		}
+		i.FullContent = v
+		return NewExtractFullContentSuccessful(i)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type ExtractFullContent struct {
+	FeedItem rss.FeedItem
+	status
+	Err error
+}
+
+func NewExtractFullContentInitial(i rss.FeedItem) ExtractFullContent {
+	return ExtractFullContent{
+		FeedItem: i,
+		status:   statusInitial,
+	}
+}
+
+func NewExtractFullContentInProgress(i rss.FeedItem) ExtractFullContent {
+	return ExtractFullContent{
+		FeedItem: i,
+		status:   statusInProgress,
+	}
+}
+
+func NewExtractFullContentSuccessful(i rss.FeedItem) ExtractFullContent {
+	return ExtractFullContent{
+		FeedItem: i,
+		status:   statusSuccessful,
+	}
+}
+
+func NewExtractFullContentFailed(i rss.FeedItem, err error) ExtractFullContent {
+	return ExtractFullContent{
+		FeedItem: i,
+		status:   statusFailed,
+		Err:      err,
+	}
+}
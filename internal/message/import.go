@@ -15,7 +15,7 @@ func ImportCmd(fileName string, repo rss.Repo) tea.Cmd {
 	cmds = append(cmds, cmd)
 
 	cmd = func() tea.Msg {
-		feeds, err := opml.Import(fileName)
+		feeds, skipped, err := opml.Import(fileName)
 		if err != nil {
 			return NewImportFailed(err)
 		}
@@ -25,7 +25,7 @@ func ImportCmd(fileName string, repo rss.Repo) tea.Cmd {
 			return NewImportFailed(err)
 		}
 
-		return NewImportSuccessful(feeds)
+		return NewImportSuccessful(feeds, skipped)
 	}
 	cmds = append(cmds, cmd)
 
@@ -34,6 +34,9 @@ func ImportCmd(fileName string, repo rss.Repo) tea.Cmd {
 
 type Import struct {
 	Feeds []rss.Feed
+	// Skipped are the URLs of commands and local files, they are not
+	// imported.
+	Skipped []string
 	status
 	Err error
 }
@@ -46,10 +49,11 @@ func NewImportInProgress() Import {
 	return Import{status: statusInProgress}
 }
 
-func NewImportSuccessful(feeds []rss.Feed) Import {
+func NewImportSuccessful(feeds []rss.Feed, skipped []string) Import {
 	return Import{
-		status: statusSuccessful,
-		Feeds:  feeds,
+		status:  statusSuccessful,
+		Feeds:   feeds,
+		Skipped: skipped,
 	}
 }
 
@@ -0,0 +1,40 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// LoadCountsCmd counts the unread items of feeds and smart feeds.
+func LoadCountsCmd(repo rss.Repo) tea.Cmd {
+	return func() tea.Msg {
+		counts, err := repo.CountUnread(context.Background(), rss.TodaySince(time.Now()))
+		if err != nil {
+			return NewLoadCountsFailed(err)
+		}
+		return NewLoadCountsSuccessful(counts)
+	}
+}
+
+type LoadCounts struct {
+	Counts rss.UnreadCounts
+	status
+	Err error
+}
+
+func NewLoadCountsSuccessful(counts rss.UnreadCounts) LoadCounts {
+	return LoadCounts{
+		Counts: counts,
+		status: statusSuccessful,
+	}
+}
+
+func NewLoadCountsFailed(err error) LoadCounts {
+	return LoadCounts{
+		status: statusFailed,
+		Err:    err,
+	}
+}
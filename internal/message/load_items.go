@@ -0,0 +1,42 @@
+package message
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// LoadItemsCmd loads the page of items selected by q.
+func LoadItemsCmd(q rss.ItemQuery, repo rss.Repo) tea.Cmd {
+	return func() tea.Msg {
+		items, err := repo.GetItems(context.Background(), q)
+		if err != nil {
+			return NewLoadItemsFailed(q, err)
+		}
+		return NewLoadItemsSuccessful(q, items)
+	}
+}
+
+type LoadItems struct {
+	Query rss.ItemQuery
+	Items []rss.FeedItem
+	status
+	Err error
+}
+
+func NewLoadItemsSuccessful(q rss.ItemQuery, items []rss.FeedItem) LoadItems {
+	return LoadItems{
+		Query:  q,
+		Items:  items,
+		status: statusSuccessful,
+	}
+}
+
+func NewLoadItemsFailed(q rss.ItemQuery, err error) LoadItems {
+	return LoadItems{
+		Query:  q,
+		status: statusFailed,
+		Err:    err,
+	}
+}
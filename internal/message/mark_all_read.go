@@ -1,29 +1,27 @@
 package message
 
 import (
+	"context"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lakerszhy/rssx/internal/rss"
 )
 
-func MarkAllReadCmd(itemIDs []int64, repo rss.Repo) tea.Cmd {
+// MarkAllReadCmd marks all items of f read, f may be a smart feed.
+func MarkAllReadCmd(f rss.Feed, repo rss.Repo) tea.Cmd {
 	var cmds []tea.Cmd
 
 	cmd := func() tea.Msg {
-		return NewMarkAllReadSuccessful(itemIDs)
-	}
-	cmds = append(cmds, cmd)
-
-	cmd = func() tea.Msg {
-		return NewMarkAllReadInProgress(itemIDs)
+		return NewMarkAllReadInProgress(f)
 	}
 	cmds = append(cmds, cmd)
 
 	cmd = func() tea.Msg {
-		err := repo.MarkAllRead(itemIDs)
+		err := repo.MarkAllRead(context.Background(), rss.NewItemQuery(f, 0))
 		if err != nil {
-			return NewMarkAllReadFailed(itemIDs, err)
+			return NewMarkAllReadFailed(f, err)
 		}
-		return nil
+		return NewMarkAllReadSuccessful(f)
 	}
 	cmds = append(cmds, cmd)
 
@@ -31,29 +29,29 @@ func MarkAllReadCmd(itemIDs []int64, repo rss.Repo) tea.Cmd {
 }
 
 type MarkAllRead struct {
-	ItemIDs []int64
-	Err     error
+	Feed rss.Feed
+	Err  error
 	status
 }
 
-func NewMarkAllReadInProgress(itemIDs []int64) MarkAllRead {
+func NewMarkAllReadInProgress(f rss.Feed) MarkAllRead {
 	return MarkAllRead{
-		ItemIDs: itemIDs,
-		status:  statusInProgress,
+		Feed:   f,
+		status: statusInProgress,
 	}
 }
 
-func NewMarkAllReadSuccessful(itemIDs []int64) MarkAllRead {
+func NewMarkAllReadSuccessful(f rss.Feed) MarkAllRead {
 	return MarkAllRead{
-		ItemIDs: itemIDs,
-		status:  statusSuccessful,
+		Feed:   f,
+		status: statusSuccessful,
 	}
 }
 
-func NewMarkAllReadFailed(itemIDs []int64, err error) MarkAllRead {
+func NewMarkAllReadFailed(f rss.Feed, err error) MarkAllRead {
 	return MarkAllRead{
-		ItemIDs: itemIDs,
-		Err:     err,
-		status:  statusFailed,
+		Feed:   f,
+		Err:    err,
+		status: statusFailed,
 	}
 }
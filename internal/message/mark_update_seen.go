@@ -0,0 +1,54 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func MarkUpdateSeenCmd(itemID int64, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewMarkUpdateSeenInProgress(itemID)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		err := repo.MarkUpdateSeen(itemID)
+		if err != nil {
+			return NewMarkUpdateSeenFailed(itemID, err)
+		}
+		return NewMarkUpdateSeenSuccessful(itemID)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type MarkUpdateSeen struct {
+	ItemID int64
+	Err    error
+	status
+}
+
+func NewMarkUpdateSeenInProgress(itemID int64) MarkUpdateSeen {
+	return MarkUpdateSeen{
+		ItemID: itemID,
+		status: statusInProgress,
+	}
+}
+
+func NewMarkUpdateSeenSuccessful(itemID int64) MarkUpdateSeen {
+	return MarkUpdateSeen{
+		ItemID: itemID,
+		status: statusSuccessful,
+	}
+}
+
+func NewMarkUpdateSeenFailed(itemID int64, err error) MarkUpdateSeen {
+	return MarkUpdateSeen{
+		ItemID: itemID,
+		Err:    err,
+		status: statusFailed,
+	}
+}
@@ -1,6 +1,7 @@
 package message
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -11,7 +12,9 @@ import (
 	"github.com/lakerszhy/rssx/internal/rss"
 )
 
-func ParseMDCmd(i rss.FeedItem, wordWrap int) tea.Cmd {
+// ParseMDCmd renders i for preview. Items of the list have no content,
+// the whole item is loaded first and comes back with the message.
+func ParseMDCmd(i rss.FeedItem, repo rss.Repo, wordWrap int) tea.Cmd {
 	var cmds []tea.Cmd
 
 	cmd := func() tea.Msg {
@@ -20,14 +23,16 @@ func ParseMDCmd(i rss.FeedItem, wordWrap int) tea.Cmd {
 	cmds = append(cmds, cmd)
 
 	cmd = func() tea.Msg {
-		v := i.Content
-		if v == "" {
-			v = i.Description
+		// Items of the broken feed are not stored.
+		if !i.IsBrokenFeed() {
+			full, err := repo.GetItem(context.Background(), i.ID)
+			if err != nil {
+				return NewParseMDFailed(i, err)
+			}
+			i = full
 		}
 
-		// \u200B: ZERO WIDTH SPACE, can cause width not correct
-		v = strings.ReplaceAll(v, "\u200B", "")
-		v, err := md.ConvertString(v)
+		v, err := toMD(i.Body())
 		if err != nil {
 			return NewParseMDFailed(i, err)
 		}
@@ -43,10 +48,35 @@ func ParseMDCmd(i rss.FeedItem, wordWrap int) tea.Cmd {
 
 		var b strings.Builder
 		b.WriteString(fmt.Sprintf("# %s\n", i.Title))
-		b.WriteString(fmt.Sprintf("%s\n\n", i.PublishedAt.Format(time.DateTime)))
+		b.WriteString(i.Date().Format(time.DateTime))
+		if author := i.Author(); author != "" {
+			b.WriteString(fmt.Sprintf(" by %s", author))
+		}
+		b.WriteString("\n\n")
+		writeMeta(&b, i)
 		b.WriteString("---\n")
 		b.WriteString(v)
 
+		// Show what changed since the version that was read.
+		if i.IsUpdated && i.ReadContent != "" {
+			old, err := toMD(i.ReadContent)
+			if err != nil {
+				return NewParseMDFailed(i, err)
+			}
+			// Full content is not what was read, compare with the feed.
+			cur, err := toMD(i.FeedBody())
+			if err != nil {
+				return NewParseMDFailed(i, err)
+			}
+			if lines := diffLines(old, cur); len(lines) > 0 {
+				b.WriteString("\n\n---\n")
+				b.WriteString("## Changes since you read it\n")
+				b.WriteString("```diff\n")
+				b.WriteString(strings.Join(lines, "\n"))
+				b.WriteString("\n```\n")
+			}
+		}
+
 		v, err = r.Render(b.String())
 		if err != nil {
 			return NewParseMDFailed(i, err)
@@ -59,6 +89,39 @@ func ParseMDCmd(i rss.FeedItem, wordWrap int) tea.Cmd {
 	return tea.Sequence(cmds...)
 }
 
+// writeMeta writes categories and attachments of i to the header.
+func writeMeta(b *strings.Builder, i rss.FeedItem) {
+	if len(i.Categories) > 0 {
+		b.WriteString(fmt.Sprintf("Categories: %s\n\n", strings.Join(i.Categories, ", ")))
+	}
+	if len(i.Enclosures) == 0 {
+		return
+	}
+
+	b.WriteString("Attachments:\n")
+	for _, e := range i.Enclosures {
+		var info []string
+		if e.Type != "" {
+			info = append(info, e.Type)
+		}
+		if size := e.Size(); size != "" {
+			info = append(info, size)
+		}
+		v := fmt.Sprintf("- [%s](%s)", e.Name(), e.URL)
+		if len(info) > 0 {
+			v = fmt.Sprintf("%s (%s)", v, strings.Join(info, ", "))
+		}
+		b.WriteString(v + "\n")
+	}
+	b.WriteString("\n")
+}
+
+func toMD(v string) (string, error) {
+	// \u200B: ZERO WIDTH SPACE, can cause width not correct
+	v = strings.ReplaceAll(v, "\u200B", "")
+	return md.ConvertString(v)
+}
+
 type ParseMD struct {
 	FeedItem rss.FeedItem
 	MD       string
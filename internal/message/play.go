@@ -0,0 +1,76 @@
+package message
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/player"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// QueuePlay asks to play the media of the item.
+type QueuePlay struct {
+	FeedItem rss.FeedItem
+}
+
+func NewQueuePlay(i rss.FeedItem) QueuePlay {
+	return QueuePlay{FeedItem: i}
+}
+
+// WaitPlayedCmd waits for the next track of p to stop playing,
+// call it again after each Played message to keep watching.
+func WaitPlayedCmd(p *player.Player) tea.Cmd {
+	return func() tea.Msg {
+		return Played(<-p.Finished())
+	}
+}
+
+type Played player.Finished
+
+func MarkPlayedCmd(itemID int64, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewMarkPlayedInProgress(itemID)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		err := repo.MarkPlayed(context.Background(), itemID)
+		if err != nil {
+			return NewMarkPlayedFailed(itemID, err)
+		}
+		return NewMarkPlayedSuccessful(itemID)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type MarkPlayed struct {
+	ItemID int64
+	Err    error
+	status
+}
+
+func NewMarkPlayedInProgress(itemID int64) MarkPlayed {
+	return MarkPlayed{
+		ItemID: itemID,
+		status: statusInProgress,
+	}
+}
+
+func NewMarkPlayedSuccessful(itemID int64) MarkPlayed {
+	return MarkPlayed{
+		ItemID: itemID,
+		status: statusSuccessful,
+	}
+}
+
+func NewMarkPlayedFailed(itemID int64, err error) MarkPlayed {
+	return MarkPlayed{
+		ItemID: itemID,
+		Err:    err,
+		status: statusFailed,
+	}
+}
@@ -1,6 +1,10 @@
 package message
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -15,74 +19,229 @@ func RefreshTickCmd(d time.Duration) tea.Cmd {
 	})
 }
 
-func RefreshCmd(feeds []rss.Feed, repo rss.Repo) tea.Cmd {
-	total := len(feeds)
-	if total == 0 {
+// RefreshCmd refreshes feeds with at most workers feeds in flight,
+// each feed is scheduled for its next refresh based on interval and
+// pruned by retention.
+// Progress is delivered as Refresh messages, call Refresh.Next to
+// wait for the following one and Refresh.Cancel to stop.
+func RefreshCmd(feeds []rss.Feed, repo rss.Repo, fetcher *rss.Fetcher,
+	workers int, interval time.Duration, retention rss.Retention) tea.Cmd {
+	if len(feeds) == 0 {
 		return nil
 	}
 
-	results := make([]FeedRefreshResult, 0, len(feeds))
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &refresher{
+		repo:      repo,
+		fetcher:   fetcher,
+		workers:   max(workers, 1),
+		interval:  interval,
+		retention: retention,
+		cancel:    cancel,
+		updates:   make(chan Refresh),
+	}
+	go r.run(ctx, feeds)
+
+	return waitRefreshCmd(r.updates)
+}
+
+func waitRefreshCmd(updates <-chan Refresh) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+type refresher struct {
+	repo      rss.Repo
+	fetcher   *rss.Fetcher
+	workers   int
+	interval  time.Duration
+	retention rss.Retention
+	cancel    context.CancelFunc
+	updates   chan Refresh
+}
+
+// run is the only place results are collected. Workers hand their
+// results over a channel, so no progress is lost or duplicated.
+func (r *refresher) run(ctx context.Context, feeds []rss.Feed) {
+	defer close(r.updates)
+	defer r.cancel()
 
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
+	total := len(feeds)
+	jobs := make(chan rss.Feed)
+	done := make(chan FeedRefreshResult)
 
-	cmd = func() tea.Msg {
-		return NewRefreshInProgress(total, results)
+	var wg sync.WaitGroup
+	for range min(r.workers, total) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				done <- refreshFeed(ctx, f, r.repo, r.fetcher, r.interval, r.retention)
+			}
+		}()
 	}
-	cmds = append(cmds, cmd)
 
-	for _, f := range feeds {
-		cmd = func() tea.Msg {
-			results = append(results, refreshFeed(f, repo))
-			return NewRefreshInProgress(total, results)
+	go func() {
+		defer func() {
+			close(jobs)
+			wg.Wait()
+			close(done)
+		}()
+		for _, f := range feeds {
+			select {
+			case jobs <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make([]FeedRefreshResult, 0, total)
+	r.updates <- NewRefreshInProgress(total, nil, r)
+
+	for ret := range done {
+		// Feeds aborted by cancel are not worth reporting.
+		if ret.IsFailed() && ctx.Err() != nil {
+			continue
+		}
+
+		results = append(results, ret)
+		// Each message owns its results, the slice keeps growing here.
+		snapshot := slices.Clone(results)
+		if len(snapshot) == total {
+			r.updates <- NewRefreshSuccessful(total, snapshot)
+		} else {
+			r.updates <- NewRefreshInProgress(total, snapshot, r)
 		}
-		cmds = append(cmds, cmd)
 	}
 
-	return tea.Batch(cmds...)
+	if len(results) < total {
+		r.updates <- NewRefreshCancelled(total, results)
+	}
 }
 
-func refreshFeed(f rss.Feed, repo rss.Repo) FeedRefreshResult {
-	newFeed, err := rss.ParseURL(f.FeedURL)
+func refreshFeed(ctx context.Context, f rss.Feed, repo rss.Repo,
+	fetcher *rss.Fetcher, interval time.Duration, retention rss.Retention) FeedRefreshResult {
+	newFeed, err := fetcher.Fetch(ctx, f)
+	notModified := errors.Is(err, rss.ErrNotModified)
+	if err != nil && !notModified {
+		if ctx.Err() == nil {
+			f.RecordFailure(time.Now(), interval, err)
+			err = errors.Join(err, repo.UpdateFetchState(ctx, f))
+		}
+		return newFeedRefreshResultFailed(f, err)
+	}
+
+	movedFrom, err := moveFeed(ctx, &f, newFeed.MovedTo, repo)
 	if err != nil {
 		return newFeedRefreshResultFailed(f, err)
 	}
 
-	// only insert new items.
-	var newItems []rss.FeedItem
-	for _, i := range newFeed.Items {
-		found := false
-		for _, j := range f.Items {
-			if j.Link == i.Link {
-				found = true
-				break
-			}
+	if notModified {
+		f.MaxAge = newFeed.MaxAge
+		f.LastStatus = newFeed.LastStatus
+		f.RecordSuccess(time.Now())
+		f.Schedule(time.Now(), interval)
+		pruned, err := repo.PruneItems(ctx, f.ID, retention.Of(f))
+		if err != nil {
+			return newFeedRefreshResultFailed(f, err)
 		}
-		if !found {
-			newItems = append(newItems, i)
+		if err = repo.UpdateFetchState(ctx, f); err != nil {
+			return newFeedRefreshResultFailed(f, err)
 		}
+		return newFeedRefreshResultSuccessful(f, movedFrom, pruned)
 	}
 
-	newItems, err = repo.InsertItems(f.ID, newItems)
+	// Store skips items it already has, only new and updated items come back.
+	saved, err := repo.InsertItems(ctx, f.ID, newFeed.Items)
 	if err != nil {
 		return newFeedRefreshResultFailed(f, err)
 	}
 
-	f.Items = append(f.Items, newItems...)
-	return newFeedRefreshResultSuccessful(f)
+	if f.ExtractFullContent {
+		extractFullContent(ctx, saved, f.Request, repo, fetcher)
+	}
+	f.Merge(saved)
+
+	// Save validators only after items are stored, otherwise
+	// a failed insert would be hidden by the next 304.
+	f.ETag = newFeed.ETag
+	f.LastModified = newFeed.LastModified
+	f.TTL = newFeed.TTL
+	f.MaxAge = newFeed.MaxAge
+	f.LastStatus = newFeed.LastStatus
+	f.RecordSuccess(time.Now())
+	f.Schedule(time.Now(), interval)
+	pruned, err := repo.PruneItems(ctx, f.ID, retention.Of(f))
+	if err != nil {
+		return newFeedRefreshResultFailed(f, err)
+	}
+	if err = repo.UpdateFetchState(ctx, f); err != nil {
+		return newFeedRefreshResultFailed(f, err)
+	}
+
+	return newFeedRefreshResultSuccessful(f, movedFrom, pruned)
+}
+
+// maxExtract is how many articles a refresh extracts for a feed at
+// most, so the first refresh of a long feed doesn't hold up the others.
+const maxExtract = 10
+
+// extractFullContent saves the article of the first maxExtract items.
+// Items whose article isn't extracted keep the feed content, so
+// failures don't fail the refresh.
+func extractFullContent(ctx context.Context, items []rss.FeedItem, r rss.Request,
+	repo rss.Repo, fetcher *rss.Fetcher) {
+	extracted := 0
+	for i := range items {
+		if items[i].Link == "" {
+			continue
+		}
+		if extracted == maxExtract || ctx.Err() != nil {
+			return
+		}
+		extracted++
+
+		v, err := fetcher.Extract(ctx, items[i].Link, r)
+		if err != nil {
+			continue
+		}
+		if err = repo.SetFullContent(ctx, items[i].ID, v); err != nil {
+			continue
+		}
+		items[i].FullContent = v
+	}
+}
+
+// moveFeed saves the new URL of a feed that moved permanently, so later
+// refreshes and exported OPML use it. The old URL is returned.
+func moveFeed(ctx context.Context, f *rss.Feed, movedTo string, repo rss.Repo) (string, error) {
+	if movedTo == "" || movedTo == f.FeedURL {
+		return "", nil
+	}
+	if err := repo.UpdateFeedURL(ctx, f.ID, movedTo); err != nil {
+		return "", err
+	}
+
+	movedFrom := f.FeedURL
+	f.FeedURL = movedTo
+	return movedFrom, nil
 }
 
 type Refresh struct {
 	Results []FeedRefreshResult
 	Total   int
 	status
+	refresher *refresher
 }
 
-func NewRefreshInProgress(total int, results []FeedRefreshResult) Refresh {
+func NewRefreshInProgress(total int, results []FeedRefreshResult, r *refresher) Refresh {
 	return Refresh{
-		Results: results,
-		Total:   total,
-		status:  statusInProgress,
+		Results:   results,
+		Total:     total,
+		status:    statusInProgress,
+		refresher: r,
 	}
 }
 
@@ -94,16 +253,45 @@ func NewRefreshSuccessful(total int, results []FeedRefreshResult) Refresh {
 	}
 }
 
+func NewRefreshCancelled(total int, results []FeedRefreshResult) Refresh {
+	return Refresh{
+		Results: results,
+		Total:   total,
+		status:  statusCancelled,
+	}
+}
+
+// Next waits for the next progress of an in progress refresh.
+func (r Refresh) Next() tea.Cmd {
+	if !r.IsInProgress() || r.refresher == nil {
+		return nil
+	}
+	return waitRefreshCmd(r.refresher.updates)
+}
+
+// Cancel stops an in progress refresh, feeds not refreshed yet are skipped.
+func (r Refresh) Cancel() {
+	if r.IsInProgress() && r.refresher != nil {
+		r.refresher.cancel()
+	}
+}
+
 type FeedRefreshResult struct {
 	Feed rss.Feed
-	Err  error
+	// MovedFrom is the old URL of a feed that moved permanently.
+	MovedFrom string
+	// Pruned is how many items retention dropped.
+	Pruned int
+	Err    error
 	status
 }
 
-func newFeedRefreshResultSuccessful(f rss.Feed) FeedRefreshResult {
+func newFeedRefreshResultSuccessful(f rss.Feed, movedFrom string, pruned int) FeedRefreshResult {
 	return FeedRefreshResult{
-		Feed:   f,
-		status: statusSuccessful,
+		Feed:      f,
+		MovedFrom: movedFrom,
+		Pruned:    pruned,
+		status:    statusSuccessful,
 	}
 }
 
@@ -0,0 +1,148 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+  <title>Test</title>
+  <item><title>Item</title><link>%s/item</link></item>
+</channel>
+</rss>`
+
+type fakeRepo struct {
+	rss.Repo
+	mu       sync.Mutex
+	inserted map[int64]int
+}
+
+func (r *fakeRepo) InsertItems(_ context.Context, feedID int64, items []rss.FeedItem) ([]rss.FeedItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inserted[feedID] += len(items)
+	return items, nil
+}
+
+func (r *fakeRepo) UpdateFetchState(context.Context, rss.Feed) error {
+	return nil
+}
+
+func (r *fakeRepo) PruneItems(context.Context, int64, rss.Retention) (int, error) {
+	return 0, nil
+}
+
+func (r *fakeRepo) UpdateFeedURL(context.Context, int64, string) error {
+	return nil
+}
+
+func (r *fakeRepo) SetFullContent(context.Context, int64, string) error {
+	return nil
+}
+
+func newTestFetcher(t *testing.T) *rss.Fetcher {
+	t.Helper()
+	f, err := rss.NewFetcher(rss.FetcherOptions{})
+	require.NoError(t, err)
+	return f
+}
+
+func TestRefreshCmd(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, testRSS, srv.URL)
+	}))
+	defer srv.Close()
+
+	const total = 20
+	feeds := make([]rss.Feed, 0, total)
+	for i := range total {
+		feeds = append(feeds, rss.Feed{ID: int64(i + 1), FeedURL: srv.URL})
+	}
+	repo := &fakeRepo{inserted: map[int64]int{}}
+
+	msg, ok := RefreshCmd(feeds, repo, newTestFetcher(t), 4, time.Minute, rss.Retention{})().(Refresh)
+	require.True(t, ok)
+
+	progress := 0
+	for msg.IsInProgress() {
+		assert.Len(t, msg.Results, progress)
+		progress++
+		msg, ok = msg.Next()().(Refresh)
+		require.True(t, ok)
+	}
+
+	require.True(t, msg.IsSuccessful())
+	assert.Equal(t, total, progress)
+	assert.Len(t, msg.Results, total)
+
+	seen := map[int64]bool{}
+	for _, ret := range msg.Results {
+		require.NoError(t, ret.Err)
+		assert.False(t, seen[ret.Feed.ID])
+		seen[ret.Feed.ID] = true
+		assert.Equal(t, 1, repo.inserted[ret.Feed.ID])
+		assert.False(t, ret.Feed.IsDue(time.Now()))
+	}
+}
+
+func TestRefreshCmdCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	feeds := []rss.Feed{
+		{ID: 1, FeedURL: srv.URL},
+		{ID: 2, FeedURL: srv.URL},
+		{ID: 3, FeedURL: srv.URL},
+	}
+	repo := &fakeRepo{inserted: map[int64]int{}}
+
+	msg, ok := RefreshCmd(feeds, repo, newTestFetcher(t), 2, time.Minute, rss.Retention{})().(Refresh)
+	require.True(t, ok)
+	require.True(t, msg.IsInProgress())
+
+	msg.Cancel()
+	for msg.IsInProgress() {
+		msg, ok = msg.Next()().(Refresh)
+		require.True(t, ok)
+	}
+
+	assert.True(t, msg.IsCancelled())
+	assert.Empty(t, msg.Results)
+	assert.Empty(t, repo.inserted)
+}
+
+func TestExtractFullContent(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		fmt.Fprint(w, "<html><body><p>Article</p></body></html>")
+	}))
+	defer srv.Close()
+
+	items := make([]rss.FeedItem, 0, maxExtract+5)
+	for i := range maxExtract + 5 {
+		items = append(items, rss.FeedItem{ID: int64(i + 1), Link: fmt.Sprintf("%s/%d", srv.URL, i)})
+	}
+	repo := &fakeRepo{inserted: map[int64]int{}}
+
+	// The first refresh of a long feed extracts its first items only.
+	extractFullContent(t.Context(), items, rss.Request{}, repo, newTestFetcher(t))
+	assert.Equal(t, maxExtract, requests)
+}
@@ -0,0 +1,70 @@
+package message
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// SearchItemsCmd searches all stored items for query, at most limit
+// results come back.
+func SearchItemsCmd(query string, limit int, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewSearchItemsInProgress(query)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		results, err := repo.SearchItems(context.Background(), query, limit)
+		if err != nil {
+			return NewSearchItemsFailed(query, err)
+		}
+		return NewSearchItemsSuccessful(query, results)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type SearchItems struct {
+	Query   string
+	Results []rss.SearchResult
+	status
+	Err error
+}
+
+func NewSearchItemsInProgress(query string) SearchItems {
+	return SearchItems{
+		Query:  query,
+		status: statusInProgress,
+	}
+}
+
+func NewSearchItemsSuccessful(query string, results []rss.SearchResult) SearchItems {
+	return SearchItems{
+		Query:   query,
+		Results: results,
+		status:  statusSuccessful,
+	}
+}
+
+func NewSearchItemsFailed(query string, err error) SearchItems {
+	return SearchItems{
+		Query:  query,
+		status: statusFailed,
+		Err:    err,
+	}
+}
+
+// OpenItem asks to show an item in the item and preview panels, e.g. a
+// search result.
+type OpenItem struct {
+	FeedItem rss.FeedItem
+}
+
+func NewOpenItem(i rss.FeedItem) OpenItem {
+	return OpenItem{FeedItem: i}
+}
@@ -4,8 +4,20 @@ import "github.com/lakerszhy/rssx/internal/rss"
 
 type SelectFeed struct {
 	Feed *rss.Feed
+	// Reload loads the items of the feed again, it got new items.
+	Reload bool
+	// Item is selected once the items down to it are loaded.
+	Item *rss.FeedItem
 }
 
 func NewSelectFeed(f *rss.Feed) SelectFeed {
 	return SelectFeed{Feed: f}
 }
+
+func NewReloadFeed(f *rss.Feed) SelectFeed {
+	return SelectFeed{Feed: f, Reload: true}
+}
+
+func NewSelectFeedOpenItem(f *rss.Feed, i rss.FeedItem) SelectFeed {
+	return SelectFeed{Feed: f, Item: &i}
+}
@@ -5,6 +5,7 @@ const (
 	statusInProgress
 	statusSuccessful
 	statusFailed
+	statusCancelled
 )
 
 type status int
@@ -24,3 +25,7 @@ func (s status) IsSuccessful() bool {
 func (s status) IsFailed() bool {
 	return s == statusFailed
 }
+
+func (s status) IsCancelled() bool {
+	return s == statusCancelled
+}
@@ -0,0 +1,56 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func ToogleFullContentCmd(f rss.Feed, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewToogleFullContentInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		v := !f.ExtractFullContent
+		err := repo.SetExtractFullContent(f.ID, v)
+		if err != nil {
+			return NewToogleFullContentFailed(f, err)
+		}
+		f.ExtractFullContent = v
+		return NewToogleFullContentSuccessful(f)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type ToogleFullContent struct {
+	Feed rss.Feed
+	status
+	Err error
+}
+
+func NewToogleFullContentInProgress(f rss.Feed) ToogleFullContent {
+	return ToogleFullContent{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewToogleFullContentSuccessful(f rss.Feed) ToogleFullContent {
+	return ToogleFullContent{
+		Feed:   f,
+		status: statusSuccessful,
+	}
+}
+
+func NewToogleFullContentFailed(f rss.Feed, err error) ToogleFullContent {
+	return ToogleFullContent{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
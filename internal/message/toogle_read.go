@@ -9,11 +9,6 @@ func ToogleReadCmd(itemID int64, repo rss.Repo) tea.Cmd {
 	var cmds []tea.Cmd
 
 	cmd := func() tea.Msg {
-		return NewToogleReadSuccessful(itemID)
-	}
-	cmds = append(cmds, cmd)
-
-	cmd = func() tea.Msg {
 		return NewToogleReadInProgress(itemID)
 	}
 	cmds = append(cmds, cmd)
@@ -23,7 +18,7 @@ func ToogleReadCmd(itemID int64, repo rss.Repo) tea.Cmd {
 		if err != nil {
 			return NewToogleReadFailed(itemID, err)
 		}
-		return nil
+		return NewToogleReadSuccessful(itemID)
 	}
 	cmds = append(cmds, cmd)
 
@@ -9,11 +9,6 @@ func ToogleStarredCmd(itemID int64, repo rss.Repo) tea.Cmd {
 	var cmds []tea.Cmd
 
 	cmd := func() tea.Msg {
-		return NewToogleStarredSuccessful(itemID)
-	}
-	cmds = append(cmds, cmd)
-
-	cmd = func() tea.Msg {
 		return NewToogleStarredInProgress(itemID)
 	}
 	cmds = append(cmds, cmd)
@@ -23,7 +18,7 @@ func ToogleStarredCmd(itemID int64, repo rss.Repo) tea.Cmd {
 		if err != nil {
 			return NewToogleStarredFailed(itemID, err)
 		}
-		return nil
+		return NewToogleStarredSuccessful(itemID)
 	}
 	cmds = append(cmds, cmd)
 
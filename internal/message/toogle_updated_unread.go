@@ -0,0 +1,56 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func ToogleUpdatedUnreadCmd(f rss.Feed, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewToogleUpdatedUnreadInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		v := !f.MarkUpdatedUnread
+		err := repo.SetMarkUpdatedUnread(f.ID, v)
+		if err != nil {
+			return NewToogleUpdatedUnreadFailed(f, err)
+		}
+		f.MarkUpdatedUnread = v
+		return NewToogleUpdatedUnreadSuccessful(f)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type ToogleUpdatedUnread struct {
+	Feed rss.Feed
+	status
+	Err error
+}
+
+func NewToogleUpdatedUnreadInProgress(f rss.Feed) ToogleUpdatedUnread {
+	return ToogleUpdatedUnread{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewToogleUpdatedUnreadSuccessful(f rss.Feed) ToogleUpdatedUnread {
+	return ToogleUpdatedUnread{
+		Feed:   f,
+		status: statusSuccessful,
+	}
+}
+
+func NewToogleUpdatedUnreadFailed(f rss.Feed, err error) ToogleUpdatedUnread {
+	return ToogleUpdatedUnread{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
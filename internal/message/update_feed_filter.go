@@ -0,0 +1,66 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func UpdateFeedFilterCmd(f rss.Feed, filter string, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewUpdateFeedFilterInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		err := repo.UpdateFeedFilter(f.ID, filter)
+		if err != nil {
+			return NewUpdateFeedFilterFailed(f, err)
+		}
+		// The store drops the validators, so the next refresh
+		// downloads the feed again for the new filter.
+		f.Filter = filter
+		f.ETag = ""
+		f.LastModified = ""
+		return NewUpdateFeedFilterSuccessful(f)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type UpdateFeedFilter struct {
+	Feed rss.Feed
+	status
+	Err error
+}
+
+func NewUpdateFeedFilterInitial(f rss.Feed) UpdateFeedFilter {
+	return UpdateFeedFilter{
+		Feed:   f,
+		status: statusInitial,
+	}
+}
+
+func NewUpdateFeedFilterInProgress(f rss.Feed) UpdateFeedFilter {
+	return UpdateFeedFilter{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewUpdateFeedFilterSuccessful(f rss.Feed) UpdateFeedFilter {
+	return UpdateFeedFilter{
+		Feed:   f,
+		status: statusSuccessful,
+	}
+}
+
+func NewUpdateFeedFilterFailed(f rss.Feed, err error) UpdateFeedFilter {
+	return UpdateFeedFilter{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
@@ -0,0 +1,62 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func UpdateFeedRequestCmd(f rss.Feed, r rss.Request, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewUpdateFeedRequestInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		err := repo.UpdateFeedRequest(f.ID, r)
+		if err != nil {
+			return NewUpdateFeedRequestFailed(f, err)
+		}
+		f.Request = r
+		return NewUpdateFeedRequestSuccessful(f)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type UpdateFeedRequest struct {
+	Feed rss.Feed
+	status
+	Err error
+}
+
+func NewUpdateFeedRequestInitial(f rss.Feed) UpdateFeedRequest {
+	return UpdateFeedRequest{
+		Feed:   f,
+		status: statusInitial,
+	}
+}
+
+func NewUpdateFeedRequestInProgress(f rss.Feed) UpdateFeedRequest {
+	return UpdateFeedRequest{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewUpdateFeedRequestSuccessful(f rss.Feed) UpdateFeedRequest {
+	return UpdateFeedRequest{
+		Feed:   f,
+		status: statusSuccessful,
+	}
+}
+
+func NewUpdateFeedRequestFailed(f rss.Feed, err error) UpdateFeedRequest {
+	return UpdateFeedRequest{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
@@ -0,0 +1,63 @@
+package message
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+func UpdateFeedRetentionCmd(f rss.Feed, r rss.Retention, repo rss.Repo) tea.Cmd {
+	var cmds []tea.Cmd
+
+	cmd := func() tea.Msg {
+		return NewUpdateFeedRetentionInProgress(f)
+	}
+	cmds = append(cmds, cmd)
+
+	cmd = func() tea.Msg {
+		err := repo.UpdateFeedRetention(f.ID, r)
+		if err != nil {
+			return NewUpdateFeedRetentionFailed(f, err)
+		}
+		// Items are pruned by the next refresh.
+		f.Retention = r
+		return NewUpdateFeedRetentionSuccessful(f)
+	}
+	cmds = append(cmds, cmd)
+
+	return tea.Sequence(cmds...)
+}
+
+type UpdateFeedRetention struct {
+	Feed rss.Feed
+	status
+	Err error
+}
+
+func NewUpdateFeedRetentionInitial(f rss.Feed) UpdateFeedRetention {
+	return UpdateFeedRetention{
+		Feed:   f,
+		status: statusInitial,
+	}
+}
+
+func NewUpdateFeedRetentionInProgress(f rss.Feed) UpdateFeedRetention {
+	return UpdateFeedRetention{
+		Feed:   f,
+		status: statusInProgress,
+	}
+}
+
+func NewUpdateFeedRetentionSuccessful(f rss.Feed) UpdateFeedRetention {
+	return UpdateFeedRetention{
+		Feed:   f,
+		status: statusSuccessful,
+	}
+}
+
+func NewUpdateFeedRetentionFailed(f rss.Feed, err error) UpdateFeedRetention {
+	return UpdateFeedRetention{
+		Feed:   f,
+		status: statusFailed,
+		Err:    err,
+	}
+}
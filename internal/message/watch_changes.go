@@ -0,0 +1,53 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// WatchChangesCmd checks after d which feeds other processes, e.g. a
+// second rssx, wrote to. The feeds are loaded again if there are any.
+func WatchChangesCmd(d time.Duration, repo rss.Repo) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		ids, err := repo.Changed(context.Background())
+		if err != nil {
+			return NewWatchChangesFailed(err)
+		}
+		if len(ids) == 0 {
+			return NewWatchChangesSuccessful(nil, nil)
+		}
+
+		feeds, err := repo.GetAllFeeds()
+		if err != nil {
+			return NewWatchChangesFailed(err)
+		}
+		return NewWatchChangesSuccessful(ids, feeds)
+	})
+}
+
+type WatchChanges struct {
+	// Changed is the ids of the changed feeds.
+	Changed []int64
+	// Feeds is all feeds, nil if none changed.
+	Feeds []rss.Feed
+	status
+	Err error
+}
+
+func NewWatchChangesSuccessful(changed []int64, feeds []rss.Feed) WatchChanges {
+	return WatchChanges{
+		Changed: changed,
+		Feeds:   feeds,
+		status:  statusSuccessful,
+	}
+}
+
+func NewWatchChangesFailed(err error) WatchChanges {
+	return WatchChanges{
+		status: statusFailed,
+		Err:    err,
+	}
+}
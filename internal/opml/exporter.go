@@ -1,6 +1,7 @@
 package opml
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"os"
 
@@ -19,6 +20,13 @@ func Export(feeds []rss.Feed, filePath string) error {
 			Description: "",
 			Type:        "rss",
 		}
+		if !f.Scraper.IsZero() {
+			b, err := json.Marshal(f.Scraper)
+			if err != nil {
+				return err
+			}
+			o.Scraper = string(b)
+		}
 		doc.Outlines = append(doc.Outlines, o)
 	}
 
@@ -0,0 +1,28 @@
+package opml
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportScraper(t *testing.T) {
+	feeds := []rss.Feed{
+		{Name: "feed", FeedURL: "https://example.com/feed"},
+		{
+			Name:    "page",
+			FeedURL: "https://example.com/news",
+			Scraper: rss.Scraper{Item: "article", Title: "h2", Date: "time"},
+		},
+	}
+
+	file := filepath.Join(t.TempDir(), "rssx.opml")
+	require.NoError(t, Export(feeds, file))
+
+	imported, _, err := Import(file)
+	require.NoError(t, err)
+	assert.Equal(t, feeds, imported)
+}
@@ -7,17 +7,21 @@ import (
 	"github.com/lakerszhy/rssx/internal/rss"
 )
 
-func Import(name string) ([]rss.Feed, error) {
+// Import returns the feeds of the OPML file name. Feeds not fetched
+// over HTTP, like commands and local files, are skipped and their URLs
+// returned.
+func Import(name string) ([]rss.Feed, []string, error) {
 	f, err := os.Open(name)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer f.Close()
 
 	var doc opml
 	if err = xml.NewDecoder(f).Decode(&doc); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return doc.toFeeds(), nil
+	feeds, skipped := doc.toFeeds()
+	return feeds, skipped, nil
 }
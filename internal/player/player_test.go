@@ -0,0 +1,131 @@
+package player
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "played")
+	script := filepath.Join(dir, "play.sh")
+	v := fmt.Sprintf(`#!/bin/sh
+sleep 0.1
+echo "$2" >> %s
+case "$2" in
+bad) exit 1 ;;
+quit) echo "Exiting... (Quit)" ;;
+*) echo "Exiting... (End of file)" ;;
+esac
+`, log)
+	require.NoError(t, os.WriteFile(script, []byte(v), 0o700)) //nolint:gosec // test script
+
+	p := New(script+" --no-video {url}", slog.New(slog.DiscardHandler))
+	defer p.Close()
+
+	tracks := []Track{
+		{FeedItem: rss.FeedItem{ID: 1}, Media: "a.mp3"},
+		{FeedItem: rss.FeedItem{ID: 2}, Media: "bad"},
+		{FeedItem: rss.FeedItem{ID: 3}, Media: "quit"},
+		{FeedItem: rss.FeedItem{ID: 4}, Media: "d.mp3"},
+	}
+	for i, track := range tracks {
+		n, err := p.Enqueue(track)
+		require.NoError(t, err)
+		assert.Equal(t, i, n)
+	}
+	n, err := p.Enqueue(tracks[2])
+	require.NoError(t, err)
+	assert.Equal(t, -1, n)
+	assert.Len(t, p.Queue(), 4)
+
+	for _, track := range tracks {
+		select {
+		case f := <-p.Finished():
+			assert.Equal(t, track.FeedItem.ID, f.Track.FeedItem.ID)
+			assert.Equal(t, track.Media == "bad", f.Err != nil)
+			// Only media played to its end counts as played.
+			assert.Equal(t, strings.HasSuffix(track.Media, ".mp3"), f.Completed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	b, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "a.mp3\nbad\nquit\nd.mp3\n", string(b))
+	assert.Empty(t, p.Queue())
+}
+
+func TestPlayerSkip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "play.sh")
+	v := "#!/bin/sh\nsleep 10\necho \"Exiting... (End of file)\"\n"
+	require.NoError(t, os.WriteFile(script, []byte(v), 0o700)) //nolint:gosec // test script
+
+	p := New(script, slog.New(slog.DiscardHandler))
+	defer p.Close()
+
+	for id := range int64(4) {
+		_, err := p.Enqueue(Track{FeedItem: rss.FeedItem{ID: id}, Media: "a.mp3"})
+		require.NoError(t, err)
+	}
+
+	// A queued track is dropped, the playing one stops and the next plays.
+	p.Skip(2)
+	p.Skip(0)
+	f := waitFinished(t, p)
+	assert.Equal(t, int64(0), f.Track.FeedItem.ID)
+	require.NoError(t, f.Err)
+	assert.False(t, f.Completed)
+
+	queue := p.Queue()
+	require.Len(t, queue, 2)
+	assert.Equal(t, int64(1), queue[0].FeedItem.ID)
+	assert.Equal(t, int64(3), queue[1].FeedItem.ID)
+
+	p.Stop()
+	f = waitFinished(t, p)
+	assert.Equal(t, int64(1), f.Track.FeedItem.ID)
+	assert.False(t, f.Completed)
+	assert.Empty(t, p.Queue())
+}
+
+func waitFinished(t *testing.T, p *Player) Finished {
+	t.Helper()
+	select {
+	case f := <-p.Finished():
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	return Finished{}
+}
+
+func TestPlayerNoCommand(t *testing.T) {
+	_, err := New(" ", slog.New(slog.DiscardHandler)).Enqueue(Track{Media: "a.mp3"})
+	require.Error(t, err)
+}
+
+func TestArgs(t *testing.T) {
+	assert.Equal(t, []string{"--no-video", "a.mp3"}, args([]string{"--no-video"}, "a.mp3"))
+	assert.Equal(t, []string{"--url=a.mp3", "-v"}, args([]string{"--url={url}", "-v"}, "a.mp3"))
+}
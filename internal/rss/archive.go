@@ -0,0 +1,177 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ErrNoArchive is returned by Backfill when a feed has no older pages.
+var ErrNoArchive = errors.New("feed has no archive")
+
+// Backfill walks the older pages of feed and calls fn with the items
+// of each page, at most maxPages older pages are fetched. Pages are found by
+// the next and prev-archive links of RFC 5005 and JSON Feed, or by the
+// ?paged=N pages of WordPress. It stops early when a page has nothing
+// new, some sites serve the first page for every page.
+func (f *Fetcher) Backfill(ctx context.Context, feed Feed, maxPages int,
+	fn func(items []FeedItem) error) error {
+	if !IsHTTPURL(feed.FeedURL) {
+		return ErrNoArchive
+	}
+
+	first, body, err := f.fetchPage(ctx, feed, feed.FeedURL)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, i := range first.Items {
+		seen[i.GUID] = true
+	}
+
+	next := archiveLink(body, feed.FeedURL)
+	paged := next == "" && isWordPress(body)
+	if next == "" && !paged {
+		return ErrNoArchive
+	}
+
+	for page := 2; page <= maxPages+1; page++ {
+		if paged {
+			next = pagedURL(feed.FeedURL, page)
+		}
+		if next == "" {
+			return nil
+		}
+
+		var part Feed
+		part, body, err = f.fetchPage(ctx, feed, next)
+		var httpErr HTTPError
+		// WordPress answers 404 after the last page.
+		if paged && errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		items := make([]FeedItem, 0, len(part.Items))
+		for _, i := range part.Items {
+			if !seen[i.GUID] {
+				seen[i.GUID] = true
+				items = append(items, i)
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		if err = fn(items); err != nil {
+			return err
+		}
+
+		if !paged {
+			next = archiveLink(body, next)
+		}
+	}
+	return nil
+}
+
+// fetchPage downloads and parses the page at u of feed, the body is
+// returned to look for links to further pages.
+func (f *Fetcher) fetchPage(ctx context.Context, feed Feed, u string) (Feed, []byte, error) {
+	page := Feed{FeedURL: u, Request: feed.Request, Filter: feed.Filter, Scraper: feed.Scraper}
+	doc, err := httpSource{fetcher: f}.download(ctx, page)
+	if err != nil {
+		return feed, nil, err
+	}
+	body := doc.bodies[0]
+	ret, err := f.parse(ctx, page, body)
+	return ret, body, err
+}
+
+// archiveLink finds the link to the older page in body, relative links
+// are resolved against pageURL.
+func archiveLink(body []byte, pageURL string) string {
+	var v string
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var jf struct {
+			NextURL string `json:"next_url"`
+		}
+		if json.Unmarshal(trimmed, &jf) == nil {
+			v = jf.NextURL
+		}
+	} else {
+		v = xmlArchiveLink(body)
+	}
+	if v == "" {
+		return ""
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return v
+	}
+	ref, err := url.Parse(v)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// xmlArchiveLink returns the href of the prev-archive link of archived
+// feeds, or of the next link of paged feeds.
+func xmlArchiveLink(body []byte) string {
+	links := make(map[string]string)
+	d := xml.NewDecoder(bytes.NewReader(body))
+	d.Strict = false
+	for {
+		t, err := d.Token()
+		if err != nil {
+			break
+		}
+		e, ok := t.(xml.StartElement)
+		if !ok || e.Name.Local != "link" {
+			continue
+		}
+		var rel, href string
+		for _, a := range e.Attr {
+			switch a.Name.Local {
+			case "rel":
+				rel = a.Value
+			case "href":
+				href = a.Value
+			}
+		}
+		if _, ok = links[rel]; !ok && href != "" {
+			links[rel] = href
+		}
+	}
+
+	if v := links["prev-archive"]; v != "" {
+		return v
+	}
+	return links["next"]
+}
+
+// isWordPress looks for the generator WordPress puts in its RSS and
+// Atom feeds.
+func isWordPress(body []byte) bool {
+	return bytes.Contains(body, []byte("wordpress.org/?v=")) ||
+		bytes.Contains(body, []byte(">WordPress</generator>"))
+}
+
+func pagedURL(feedURL string, page int) string {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("paged", strconv.Itoa(page))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
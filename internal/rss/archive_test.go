@@ -0,0 +1,89 @@
+package rss
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testArchivePage = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Archive</title>
+  %s
+  <entry><id>%d</id><title>Item %d</title></entry>
+</feed>`
+
+const testWordPressPage = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+  <title>Blog</title>
+  <generator>https://wordpress.org/?v=6.5</generator>
+  <item><guid>%d</guid><title>Item %d</title></item>
+</channel>
+</rss>`
+
+func backfillItems(t *testing.T, u string, maxPages int) ([]string, error) {
+	t.Helper()
+	var guids []string
+	err := newTestFetcher(t).Backfill(t.Context(), Feed{FeedURL: u}, maxPages, func(items []FeedItem) error {
+		for _, i := range items {
+			guids = append(guids, i.GUID)
+		}
+		return nil
+	})
+	return guids, err
+}
+
+func TestBackfillArchive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		link := ""
+		if page < 3 {
+			link = fmt.Sprintf(`<link rel="prev-archive" href="?page=%d"/>`, page+1)
+		}
+		fmt.Fprintf(w, testArchivePage, link, page, page)
+	}))
+	defer srv.Close()
+
+	guids, err := backfillItems(t, srv.URL+"/?page=0", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, guids)
+
+	guids, err = backfillItems(t, srv.URL+"/?page=0", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, guids)
+}
+
+func TestBackfillWordPress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("paged"))
+		if page > 3 {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, testWordPressPage, page, page)
+	}))
+	defer srv.Close()
+
+	guids, err := backfillItems(t, srv.URL+"/feed/", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, guids)
+}
+
+func TestBackfillNoArchive(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	_, err := backfillItems(t, srv.URL, 10)
+	require.ErrorIs(t, err, ErrNoArchive)
+
+	_, err = backfillItems(t, "exec:echo", 10)
+	require.ErrorIs(t, err, ErrNoArchive)
+}
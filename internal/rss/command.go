@@ -0,0 +1,82 @@
+package rss
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// execPrefix marks a feed URL as a command, like exec:~/bin/gen-feed.sh,
+// whose output is the feed document.
+const execPrefix = "exec:"
+
+// commandSource runs the command of a feed and reads the feed from
+// its stdout. Commands have no validators, the document is parsed
+// on every refresh.
+type commandSource struct {
+	fetcher *Fetcher
+}
+
+func (s commandSource) download(ctx context.Context, feed Feed) (document, error) {
+	command := strings.TrimSpace(strings.TrimPrefix(feed.FeedURL, execPrefix))
+	if command == "" {
+		return document{}, errors.New("empty command")
+	}
+
+	body, err := s.fetcher.run(ctx, command, nil)
+	return document{bodies: [][]byte{body}}, err
+}
+
+// run runs command in the shell with stdin as input and returns its
+// stdout. The output is limited like HTTP bodies are.
+func (f *Fetcher) run(ctx context.Context, command string, stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, expandHome(command))
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Children which keep the pipes open must not hang the refresh.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	if f.maxBodySize > 0 && int64(stdout.Len()) > f.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	return stdout.Bytes(), nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// expandHome expands a leading ~, which cmd doesn't do like sh.
+func expandHome(command string) string {
+	if command != "~" && !strings.HasPrefix(command, "~/") {
+		return command
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return command
+	}
+	return home + command[1:]
+}
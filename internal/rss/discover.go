@@ -0,0 +1,117 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrNoFeedFound is returned by Discover when a page links to no feed.
+var ErrNoFeedFound = errors.New("no feed found")
+
+// FeedLink is a feed advertised by a web page.
+type FeedLink struct {
+	URL   string
+	Title string
+	Type  string
+}
+
+// feedTypes are the link types of feeds. Plain application/json is left
+// out, WordPress links its REST API with it.
+var feedTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+}
+
+// commonPaths are tried in order when a page doesn't advertise its feed.
+var commonPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/atom.xml",
+	"/feed.xml",
+	"/index.xml",
+	"/rss",
+	"/feed.json",
+}
+
+// Discover finds the feeds of the web page u. Feeds the page links to
+// with <link rel="alternate"> are preferred, otherwise the common feed
+// paths of the site are probed and the first one that parses is returned.
+func (f *Fetcher) Discover(ctx context.Context, u string, r Request) ([]FeedLink, error) {
+	resp, err := f.get(ctx, u, r, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relative links resolve against the page after redirects.
+	links := feedLinks(doc, resp.Request.URL)
+	if len(links) > 0 {
+		return links, nil
+	}
+
+	for _, p := range commonPaths {
+		v := resp.Request.URL.ResolveReference(&url.URL{Path: p}).String()
+		feed, err := f.Fetch(ctx, Feed{FeedURL: v, Request: r})
+		if err == nil {
+			return []FeedLink{{URL: v, Title: feed.Name}}, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, ErrNoFeedFound
+}
+
+func feedLinks(doc *goquery.Document, base *url.URL) []FeedLink {
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if u, err := base.Parse(href); err == nil {
+			base = u
+		}
+	}
+
+	var links []FeedLink
+	seen := make(map[string]bool)
+	doc.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		rel := strings.Fields(strings.ToLower(s.AttrOr("rel", "")))
+		if !slices.Contains(rel, "alternate") {
+			return
+		}
+
+		typ := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		if !feedTypes[typ] {
+			return
+		}
+
+		u, err := base.Parse(strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil || seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+
+		links = append(links, FeedLink{
+			URL:   u.String(),
+			Title: strings.TrimSpace(s.AttrOr("title", "")),
+			Type:  typ,
+		})
+	})
+	return links
+}
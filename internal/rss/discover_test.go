@@ -0,0 +1,49 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverLinks(t *testing.T) {
+	const page = `<html><head>
+<link rel="alternate" type="application/rss+xml" title="RSS" href="/index.rss">
+<link rel="alternate" type="application/atom+xml" href="https://example.com/atom">
+<link rel="alternate" type="application/feed+json" href="/feed.json">
+<link rel="alternate" type="application/json" href="/wp-json/wp/v2/pages/2">
+<link rel="stylesheet" type="text/css" href="/style.css">
+</head></html>`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	links, err := newTestFetcher(t).Discover(t.Context(), srv.URL+"/blog/", Request{})
+	require.NoError(t, err)
+	assert.Equal(t, []FeedLink{
+		{URL: srv.URL + "/index.rss", Title: "RSS", Type: "application/rss+xml"},
+		{URL: "https://example.com/atom", Type: "application/atom+xml"},
+		{URL: srv.URL + "/feed.json", Type: "application/feed+json"},
+	}, links)
+}
+
+func TestDiscoverCommonPaths(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<html><head></head></html>"))
+	})
+	mux.HandleFunc("/rss.xml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	links, err := newTestFetcher(t).Discover(t.Context(), srv.URL, Request{})
+	require.NoError(t, err)
+	assert.Equal(t, []FeedLink{{URL: srv.URL + "/rss.xml", Title: "Test"}}, links)
+}
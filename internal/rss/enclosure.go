@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DownloadState is how far the download of an enclosure got.
+type DownloadState string
+
+const (
+	DownloadNone    DownloadState = ""
+	DownloadQueued  DownloadState = "queued"
+	DownloadRunning DownloadState = "running"
+	DownloadPaused  DownloadState = "paused"
+	DownloadDone    DownloadState = "done"
+	DownloadFailed  DownloadState = "failed"
+)
+
+// Enclosure is a file attached to an item, like the audio of a podcast.
+type Enclosure struct {
+	ID     int64
+	ItemID int64
+	URL    string
+	Type   string
+	// Length is the size in bytes the feed claims, 0 when unknown.
+	Length int64
+
+	DownloadState DownloadState
+	// Path is where the enclosure is downloaded to.
+	Path string
+}
+
+// IsMedia reports whether e is audio or video. Enclosures without
+// a type are assumed to be media, podcasts often omit it.
+func (e Enclosure) IsMedia() bool {
+	return e.Type == "" || strings.HasPrefix(e.Type, "audio/") || strings.HasPrefix(e.Type, "video/")
+}
+
+// Name is the file name of e, for display.
+func (e Enclosure) Name() string {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return e.URL
+	}
+	if v := path.Base(u.Path); v != "." && v != "/" {
+		return v
+	}
+	return e.URL
+}
+
+// Size formats Length for display, empty when unknown.
+func (e Enclosure) Size() string {
+	return FormatSize(e.Length)
+}
+
+// FormatSize formats n bytes in a human readable unit, empty when n
+// is unknown.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n <= 0 {
+		return ""
+	}
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,117 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ErrNoArticle is returned by Extract when a page has no article body.
+var ErrNoArticle = errors.New("no article found")
+
+// minArticleLength is the text length a candidate needs to be
+// taken as the article rather than a teaser or a comment.
+const minArticleLength = 200
+
+// noise never belongs to the article body.
+const noise = "script, style, noscript, iframe, form, nav, header, footer, aside, " +
+	"button, input, select, textarea, svg"
+
+// Extract downloads the page at link and returns the HTML of its
+// main article body. Like readability, paragraphs vote for their
+// parent by the length of their text, and the parent with the
+// most text wins.
+func (f *Fetcher) Extract(ctx context.Context, link string, r Request) (string, error) {
+	resp, err := f.get(ctx, link, r, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return extractArticle(doc, resp.Request.URL)
+}
+
+func extractArticle(doc *goquery.Document, base *url.URL) (string, error) {
+	doc.Find(noise).Remove()
+
+	article := bestCandidate(doc)
+	if article == nil {
+		return "", ErrNoArticle
+	}
+
+	// Links and images must work outside of the page.
+	article.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		absolute(s, "href", base)
+	})
+	article.Find("img[src]").Each(func(_ int, s *goquery.Selection) {
+		absolute(s, "src", base)
+	})
+
+	return article.Html()
+}
+
+func bestCandidate(doc *goquery.Document) *goquery.Selection {
+	// A single <article> is the body on most blogs.
+	if s := doc.Find("article"); s.Length() == 1 && textLength(s) >= minArticleLength {
+		return s
+	}
+
+	var best *html.Node
+	scores := make(map[*html.Node]int)
+
+	doc.Find("p, pre, blockquote").Each(func(_ int, s *goquery.Selection) {
+		n := textLength(s)
+		if n < 25 { //nolint:mnd // too short to be a paragraph
+			return
+		}
+
+		parent := s.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		node := parent.Get(0)
+
+		// Commas hint at prose rather than link lists.
+		scores[node] += n + strings.Count(s.Text(), ",")*10 //nolint:mnd // weight of comma
+		if best == nil || scores[node] > scores[best] {
+			best = node
+		}
+	})
+
+	if best == nil {
+		return nil
+	}
+	article := doc.FindNodes(best)
+	if textLength(article) < minArticleLength {
+		return nil
+	}
+	return article
+}
+
+func textLength(s *goquery.Selection) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(s.Text()), " "))
+}
+
+func absolute(s *goquery.Selection, attr string, base *url.URL) {
+	v, _ := s.Attr(attr)
+	if u, err := base.Parse(strings.TrimSpace(v)); err == nil {
+		s.SetAttr(attr, u.String())
+	}
+}
@@ -0,0 +1,49 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	paragraph := "<p>" + strings.Repeat("The article body, with some prose in it. ", 5) + "</p>"
+	page := `<html><body>
+<nav><p>` + strings.Repeat("Home, About, Archive, ", 5) + `</p></nav>
+<div id="sidebar"><p>Short teaser of another post.</p></div>
+<div id="content">` + paragraph + paragraph + `
+<p><a href="/next">Next post</a> <img src="img/a.png"></p>
+<script>var tracking = 1;</script>
+</div>
+<footer><p>` + strings.Repeat("Copyright, all rights reserved, ", 5) + `</p></footer>
+</body></html>`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	v, err := newTestFetcher(t).Extract(t.Context(), srv.URL+"/posts/1", Request{})
+	require.NoError(t, err)
+	assert.Contains(t, v, "The article body")
+	assert.Contains(t, v, `href="`+srv.URL+`/next"`)
+	assert.Contains(t, v, `src="`+srv.URL+`/posts/img/a.png"`)
+	assert.NotContains(t, v, "teaser")
+	assert.NotContains(t, v, "Archive")
+	assert.NotContains(t, v, "Copyright")
+	assert.NotContains(t, v, "tracking")
+}
+
+func TestExtractNoArticle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p>Too short to be an article.</p></body></html>`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestFetcher(t).Extract(t.Context(), srv.URL, Request{})
+	require.ErrorIs(t, err, ErrNoArticle)
+}
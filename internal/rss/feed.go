@@ -1,50 +1,145 @@
 package rss
 
 import (
+	"slices"
 	"strings"
+	"time"
 )
 
 const (
 	smartFeedID = 0
 )
 
+// SmartKind tells the smart feeds apart, they share the smart feed ID.
+type SmartKind int
+
+const (
+	SmartNone SmartKind = iota
+	SmartToday
+	SmartUnread
+	SmartStarred
+	SmartBroken
+)
+
 type Feed struct {
 	ID          int64
 	Name        string
 	FeedURL     string
 	HomePageURL string
-	Items       []FeedItem
+	Smart       SmartKind
+	// Items holds the loaded items only, feeds in the feed list have
+	// none. See Repo.GetItems.
+	Items []FeedItem
+	// UnreadCount is counted by Repo.CountUnread.
+	UnreadCount int
+	// LastPublishedAt is the date of the latest stored item.
+	LastPublishedAt time.Time
+
+	// Request overrides the HTTP requests of the feed.
+	Request Request
+	// Filter is a command the downloaded feed is piped through
+	// before it is parsed, empty means no filter.
+	Filter string
+	// Scraper builds the items of a page without feed, zero for feeds.
+	Scraper Scraper
+
+	// Cache validators of the last successful fetch.
+	ETag         string
+	LastModified string
+
+	// MarkUpdatedUnread marks items unread again when they are updated.
+	MarkUpdatedUnread bool
+	// ExtractFullContent extracts the article of new items on refresh.
+	ExtractFullContent bool
+	// Retention overrides the global retention of items.
+	Retention Retention
+
+	// TTL is how often the publisher says the feed changes.
+	TTL time.Duration
+	// MaxAge is the Cache-Control max-age of the last response, not saved.
+	MaxAge time.Duration
+	// NextRefreshAt is when the feed is due for refresh, zero means now.
+	NextRefreshAt time.Time
+	// MovedTo is where the feed permanently redirected to in the last
+	// fetch, not saved.
+	MovedTo string
+
+	// Health of the feed, kept by RecordSuccess and RecordFailure.
+	LastCheckedAt time.Time
+	LastSuccessAt time.Time
+	LastError     string
+	FailureCount  int
+	LastStatus    int
+	IsDead        bool
 }
 
 func NewTodayFeed() Feed {
 	return Feed{
-		ID:   smartFeedID,
-		Name: "⛭ Today",
+		ID:    smartFeedID,
+		Smart: SmartToday,
+		Name:  "⛭ Today",
 	}
 }
 
 func NewUnreadFeed() Feed {
 	return Feed{
-		ID:   smartFeedID,
-		Name: "⭘ Unread",
+		ID:    smartFeedID,
+		Smart: SmartUnread,
+		Name:  "⭘ Unread",
 	}
 }
 
 func NewStarredFeed() Feed {
 	return Feed{
-		ID:   smartFeedID,
-		Name: "⛤ Starred",
+		ID:    smartFeedID,
+		Smart: SmartStarred,
+		Name:  "⛤ Starred",
 	}
 }
 
-func (f Feed) UnreadCount() int {
-	count := 0
-	for _, i := range f.Items {
-		if !i.IsRead {
-			count++
+// NewBrokenFeed lists the feeds failing to refresh. Its items stand
+// for feeds, see FeedItem.IsBrokenFeed.
+func NewBrokenFeed(feeds []Feed) Feed {
+	f := Feed{
+		ID:    smartFeedID,
+		Smart: SmartBroken,
+		Name:  "⚠ Broken",
+	}
+	for _, i := range feeds {
+		if i.IsBroken() {
+			f.Items = append(f.Items, i.healthItem())
 		}
 	}
-	return count
+	return f
+}
+
+// Contains reports whether i is listed in f. Items of smart feeds
+// are matched by their state.
+func (f Feed) Contains(i FeedItem) bool {
+	switch f.Smart {
+	case SmartToday:
+		return i.IsToday()
+	case SmartUnread:
+		return !i.IsRead
+	case SmartStarred:
+		return i.IsStarred
+	case SmartBroken:
+		return i.IsBrokenFeed()
+	}
+	return i.FeedID == f.ID
+}
+
+// Is reports whether f and v are the same feed.
+func (f Feed) Is(v Feed) bool {
+	return f.ID == v.ID && f.Smart == v.Smart
+}
+
+// Unload drops the items of f, they are loaded in pages when shown.
+// The date of the latest one is kept for scheduling.
+func (f *Feed) Unload() *Feed {
+	f.LastPublishedAt = f.lastPublishedAt()
+	f.Items = nil
+	return f
 }
 
 func (f *Feed) ToogleRead(itemID int64) *Feed {
@@ -59,14 +154,12 @@ func (f *Feed) ToogleRead(itemID int64) *Feed {
 	return f
 }
 
-func (f *Feed) MarkAllRead(itemIDs []int64) *Feed {
+// MarkAllRead marks the items of f listed in feed read.
+func (f *Feed) MarkAllRead(feed Feed) *Feed {
 	items := make([]FeedItem, 0, len(f.Items))
 	for _, i := range f.Items {
-		for _, id := range itemIDs {
-			if i.ID == id {
-				i.MarkRead()
-				break
-			}
+		if feed.Contains(i) {
+			i.MarkRead()
 		}
 		items = append(items, i)
 	}
@@ -86,6 +179,59 @@ func (f *Feed) ToogleStarred(itemID int64) *Feed {
 	return f
 }
 
+func (f *Feed) MarkUpdateSeen(itemID int64) *Feed {
+	items := make([]FeedItem, 0, len(f.Items))
+	for _, i := range f.Items {
+		if i.ID == itemID {
+			i.MarkUpdateSeen()
+		}
+		items = append(items, i)
+	}
+	f.Items = items
+	return f
+}
+
+// Merge adds new items and replaces the stored ones by ID.
+func (f *Feed) Merge(saved []FeedItem) *Feed {
+	items := slices.Clone(f.Items)
+	for _, i := range saved {
+		idx := slices.IndexFunc(items, func(j FeedItem) bool {
+			return j.ID == i.ID
+		})
+		if idx >= 0 {
+			items[idx] = i
+		} else {
+			items = append(items, i)
+		}
+	}
+	f.Items = items
+	return f
+}
+
+func (f *Feed) MarkPlayed(itemID int64) *Feed {
+	items := make([]FeedItem, 0, len(f.Items))
+	for _, i := range f.Items {
+		if i.ID == itemID {
+			i.IsPlayed = true
+		}
+		items = append(items, i)
+	}
+	f.Items = items
+	return f
+}
+
+func (f *Feed) SetFullContent(itemID int64, v string) *Feed {
+	items := make([]FeedItem, 0, len(f.Items))
+	for _, i := range f.Items {
+		if i.ID == itemID {
+			i.FullContent = v
+		}
+		items = append(items, i)
+	}
+	f.Items = items
+	return f
+}
+
 func (f *Feed) Rename(v string) *Feed {
 	f.Name = strings.TrimSpace(v)
 	return f
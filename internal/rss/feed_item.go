@@ -9,6 +9,8 @@ import (
 
 type FeedItem struct {
 	ID          int64
+	FeedID      int64
+	GUID        string
 	FeedName    string
 	Title       string
 	Description string
@@ -16,7 +18,31 @@ type FeedItem struct {
 	Link        string
 	IsRead      bool
 	IsStarred   bool
+	IsPlayed    bool
+	// PublishedAt and UpdatedAt are zero when the feed has no date.
 	PublishedAt time.Time
+	UpdatedAt   time.Time
+	// FetchedAt is when the item was first seen.
+	FetchedAt   time.Time
+	ContentHash string
+	Authors     []string
+	Categories  []string
+	ImageURL    string
+	Enclosures  []Enclosure
+
+	// IsUpdated is set when the publisher changed the item after it was
+	// stored. ReadContent holds the version the user had read, if any.
+	IsUpdated   bool
+	ReadContent string
+
+	// FullContent is the article extracted from Link.
+	FullContent string
+}
+
+// IsBrokenFeed reports whether i is an item of the broken smart feed,
+// which stands for the feed itself and is not stored.
+func (i FeedItem) IsBrokenFeed() bool {
+	return i.ID == 0 && i.FeedID != 0
 }
 
 func (i *FeedItem) ToogleRead() {
@@ -31,12 +57,70 @@ func (i *FeedItem) ToogleStarred() {
 	i.IsStarred = !i.IsStarred
 }
 
+// Media returns the first audio or video enclosure of i.
+func (i FeedItem) Media() (Enclosure, bool) {
+	for _, e := range i.Enclosures {
+		if e.IsMedia() {
+			return e, true
+		}
+	}
+	return Enclosure{}, false
+}
+
+func (i *FeedItem) MarkUpdateSeen() {
+	i.IsUpdated = false
+	i.ReadContent = ""
+}
+
+// Body is shown in preview, the extracted article when there is one.
+func (i FeedItem) Body() string {
+	if i.FullContent != "" {
+		return i.FullContent
+	}
+	return i.FeedBody()
+}
+
+// FeedBody is the body as the feed ships it.
+func (i FeedItem) FeedBody() string {
+	if i.Content != "" {
+		return i.Content
+	}
+	return i.Description
+}
+
+// Date is when i was published, items without a publish date fall
+// back to when they were updated or first seen.
+func (i FeedItem) Date() time.Time {
+	switch {
+	case !i.PublishedAt.IsZero():
+		return i.PublishedAt
+	case !i.UpdatedAt.IsZero():
+		return i.UpdatedAt
+	}
+	return i.FetchedAt
+}
+
 func (i FeedItem) IsToday() bool {
-	return i.PublishedAt.After(time.Now().AddDate(0, 0, -1))
+	return i.Date().After(TodaySince(time.Now()))
 }
 
+// TodaySince is where the today smart feed starts at now.
+func TodaySince(now time.Time) time.Time {
+	return now.AddDate(0, 0, -1)
+}
+
+// FilterValue lets the item list be filtered by author and category too.
 func (i FeedItem) FilterValue() string {
-	return i.Title
+	v := make([]string, 0, 1+len(i.Authors)+len(i.Categories))
+	v = append(v, i.Title)
+	v = append(v, i.Authors...)
+	v = append(v, i.Categories...)
+	return strings.Join(v, " ")
+}
+
+// Author is the authors of i joined for display.
+func (i FeedItem) Author() string {
+	return strings.Join(i.Authors, ", ")
 }
 
 func (i FeedItem) PlainDescription(policy *bluemonday.Policy) string {
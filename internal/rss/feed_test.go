@@ -2,6 +2,7 @@ package rss
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -60,3 +61,25 @@ func TestToggleItemStarInFeed(t *testing.T) {
 		}
 	}
 }
+
+func TestItemDate(t *testing.T) {
+	published := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	updated := published.Add(time.Hour)
+	fetched := published.Add(2 * time.Hour)
+
+	cases := []struct {
+		name string
+		item FeedItem
+		want time.Time
+	}{
+		{name: "Published", item: FeedItem{PublishedAt: published, UpdatedAt: updated, FetchedAt: fetched}, want: published},
+		{name: "Updated", item: FeedItem{UpdatedAt: updated, FetchedAt: fetched}, want: updated},
+		{name: "Fetched", item: FeedItem{FetchedAt: fetched}, want: fetched},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, c.item.Date(), c.name)
+	}
+	assert.True(t, FeedItem{FetchedAt: time.Now()}.IsToday())
+	assert.False(t, FeedItem{PublishedAt: published, FetchedAt: time.Now()}.IsToday())
+}
@@ -0,0 +1,62 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchRequest(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	fetcher, err := NewFetcher(FetcherOptions{UserAgent: "test-agent"})
+	require.NoError(t, err)
+
+	f := ParseFeedURL(strings.Replace(srv.URL, "http://", "http://user:secret@", 1))
+	f.Request.Headers = map[string]string{"X-Token": "abc"}
+	f.Request.Cookie = "session=1"
+
+	feed, err := fetcher.Fetch(t.Context(), f)
+	require.NoError(t, err)
+	assert.Equal(t, f.Request, feed.Request)
+
+	assert.Equal(t, "test-agent", got.UserAgent())
+	assert.Equal(t, "abc", got.Header.Get("X-Token"))
+	assert.Equal(t, "session=1", got.Header.Get("Cookie"))
+	user, pass, ok := got.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "user", user)
+	assert.Equal(t, "secret", pass)
+}
+
+func TestFetchMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	fetcher, err := NewFetcher(FetcherOptions{MaxBodySize: 16})
+	require.NoError(t, err)
+
+	_, err = fetcher.ParseURL(t.Context(), srv.URL)
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders(" authorization: Bearer x ; X-Key: a:b;")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer x", "X-Key": "a:b"}, headers)
+	assert.Equal(t, "Authorization: Bearer x; X-Key: a:b", FormatHeaders(headers))
+
+	_, err = ParseHeaders("no colon")
+	require.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package rss
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fileScheme marks a feed URL as a local file, plain paths work too.
+const fileScheme = "file://"
+
+// feedExts are the files of a directory which are read as feeds.
+var feedExts = []string{".xml", ".rss", ".atom", ".json"}
+
+// fileSource reads feeds from local files. A directory is read as
+// one feed made of all feed files in it. The modification time takes
+// the place of Last-Modified to detect changes.
+type fileSource struct {
+	fetcher *Fetcher
+}
+
+func isFilePath(v string) bool {
+	for _, prefix := range []string{fileScheme, "~/", "./", "../"} {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return filepath.IsAbs(v)
+}
+
+// filePath is where the feed at v is on disk.
+func filePath(v string) (string, error) {
+	if !strings.HasPrefix(v, fileScheme) {
+		return expandHome(v), nil
+	}
+
+	u, err := url.Parse(v)
+	if err != nil {
+		return "", err
+	}
+	p := u.Path
+	// file:///C:/feed.xml has the drive after the slash.
+	if runtime.GOOS == "windows" {
+		p = strings.TrimPrefix(p, "/")
+	}
+	return filepath.FromSlash(p), nil
+}
+
+func (s fileSource) download(_ context.Context, feed Feed) (document, error) {
+	path, err := filePath(feed.FeedURL)
+	if err != nil {
+		return document{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return document{}, err
+	}
+
+	files := []string{path}
+	modTime := info.ModTime()
+	if info.IsDir() {
+		files, modTime, err = feedFiles(path, modTime)
+		if err != nil {
+			return document{}, err
+		}
+	}
+
+	// Not sent to any server, so the precision can be finer than
+	// the seconds of HTTP dates.
+	doc := document{lastModified: modTime.UTC().Format(time.RFC3339Nano)}
+	if doc.lastModified == feed.LastModified {
+		return doc, ErrNotModified
+	}
+
+	for _, i := range files {
+		body, err := s.read(i)
+		if err != nil {
+			return doc, err
+		}
+		doc.bodies = append(doc.bodies, body)
+	}
+	return doc, nil
+}
+
+func (s fileSource) read(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.ReadCloser = f
+	if s.fetcher.maxBodySize > 0 {
+		r = &limitedBody{ReadCloser: f, max: s.fetcher.maxBodySize}
+	}
+	return io.ReadAll(r)
+}
+
+// feedFiles lists the feed files of dir by name, along with the
+// latest modification time of dir and the files. Removing a file
+// changes the time of dir.
+func feedFiles(dir string, modTime time.Time) ([]string, time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, modTime, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") || !slices.Contains(feedExts, ext) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, modTime, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	return files, modTime, nil
+}
@@ -0,0 +1,64 @@
+package rss
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	require.NoError(t, os.WriteFile(path, []byte(testRSS), 0o600))
+
+	f := newTestFetcher(t)
+	for _, v := range []string{path, "file://" + filepath.ToSlash(path)} {
+		feed, err := f.Fetch(t.Context(), Feed{FeedURL: v})
+		require.NoError(t, err)
+		assert.Equal(t, v, feed.FeedURL)
+		assert.Len(t, feed.Items, 1)
+		assert.NotEmpty(t, feed.LastModified)
+
+		_, err = f.Fetch(t.Context(), feed)
+		require.ErrorIs(t, err, ErrNotModified)
+	}
+
+	// A rewritten file is fetched again.
+	feed, err := f.Fetch(t.Context(), Feed{FeedURL: path})
+	require.NoError(t, err)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	_, err = f.Fetch(t.Context(), feed)
+	require.NoError(t, err)
+}
+
+func TestFetchDirectory(t *testing.T) {
+	dir := t.TempDir()
+	second := strings.ReplaceAll(testRSS, "/1", "/2")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.xml"), []byte(testRSS), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.rss"), []byte(second), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a feed"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden.xml"), []byte("not a feed"), 0o600))
+
+	feed, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: dir})
+	require.NoError(t, err)
+	require.Len(t, feed.Items, 2)
+	assert.Equal(t, "https://example.com/1", feed.Items[0].Link)
+	assert.Equal(t, "https://example.com/2", feed.Items[1].Link)
+
+	// A directory without feed files is no feed.
+	_, err = newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: t.TempDir()})
+	require.ErrorIs(t, err, errNoDocument)
+}
+
+func TestIsHTTPURL(t *testing.T) {
+	assert.True(t, IsHTTPURL("https://example.com/feed"))
+	assert.False(t, IsHTTPURL("exec:gen-feed.sh"))
+	assert.False(t, IsHTTPURL("file:///srv/feeds/build.xml"))
+	assert.False(t, IsHTTPURL("~/feeds/build.xml"))
+	assert.False(t, IsHTTPURL(filepath.Join(os.TempDir(), "build.xml")))
+}
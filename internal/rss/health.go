@@ -0,0 +1,84 @@
+package rss
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxFailures is how many refreshes in a row may fail before
+// a feed is considered dead.
+const maxFailures = 10
+
+// IsBroken reports whether the last refresh of f failed.
+func (f Feed) IsBroken() bool {
+	return f.FailureCount > 0 || f.IsDead
+}
+
+// RecordSuccess clears the failures of f after a successful fetch.
+func (f *Feed) RecordSuccess(now time.Time) {
+	f.LastCheckedAt = now
+	f.LastSuccessAt = now
+	f.LastError = ""
+	f.FailureCount = 0
+	f.IsDead = false
+}
+
+// RecordFailure tracks a failed fetch and schedules the retry.
+// A feed is dead after maxFailures failures in a row, or at once
+// when the server says it is gone. Rate limits are no failures,
+// the retry only waits as long as the server asks.
+func (f *Feed) RecordFailure(now time.Time, base time.Duration, err error) {
+	f.LastCheckedAt = now
+	f.LastError = err.Error()
+	f.LastStatus = 0
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		f.LastStatus = httpErr.StatusCode
+	}
+	if !httpErr.isRateLimit() {
+		f.FailureCount++
+	}
+	f.IsDead = f.FailureCount >= maxFailures || f.LastStatus == http.StatusGone
+
+	f.ScheduleRetry(now, base, err)
+}
+
+// isRateLimit reports whether the server only asks to come back later.
+func (e HTTPError) isRateLimit() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.RetryAfter > 0
+}
+
+func (f Feed) healthItem() FeedItem {
+	var b strings.Builder
+	state := "Failing"
+	if f.IsDead {
+		state = "Dead, not refreshed automatically anymore"
+	}
+	fmt.Fprintf(&b, "<p>%s.</p>", state)
+	fmt.Fprintf(&b, "<p>Failed %d times in a row.</p>", f.FailureCount)
+	if f.LastStatus != 0 {
+		fmt.Fprintf(&b, "<p>Last HTTP status: %d %s</p>", f.LastStatus, http.StatusText(f.LastStatus))
+	}
+	fmt.Fprintf(&b, "<p>Last error: %s</p>", html.EscapeString(f.LastError))
+	if f.LastSuccessAt.IsZero() {
+		b.WriteString("<p>Never refreshed successfully.</p>")
+	} else {
+		fmt.Fprintf(&b, "<p>Last success: %s</p>", f.LastSuccessAt.Format(time.DateTime))
+	}
+	fmt.Fprintf(&b, "<p>Feed URL: %s</p>", html.EscapeString(f.FeedURL))
+
+	return FeedItem{
+		FeedID:      f.ID,
+		FeedName:    f.Name,
+		Title:       f.Name,
+		Description: b.String(),
+		Link:        f.FeedURL,
+		IsRead:      true,
+		PublishedAt: f.LastCheckedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package rss
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordFailure(t *testing.T) {
+	now := time.Now()
+	base := 10 * time.Minute
+	f := Feed{ID: 1, Name: "feed"}
+
+	f.RecordFailure(now, base, errors.New("timeout"))
+	assert.Equal(t, 1, f.FailureCount)
+	assert.Equal(t, now.Add(base), f.NextRefreshAt)
+	assert.True(t, f.IsBroken())
+	assert.False(t, f.IsDead)
+
+	// Backoff doubles with each failure.
+	f.RecordFailure(now, base, HTTPError{StatusCode: http.StatusInternalServerError})
+	assert.Equal(t, now.Add(2*base), f.NextRefreshAt)
+	assert.Equal(t, http.StatusInternalServerError, f.LastStatus)
+
+	for range maxFailures {
+		f.RecordFailure(now, base, errors.New("timeout"))
+	}
+	assert.True(t, f.IsDead)
+	assert.Equal(t, now.Add(maxRefreshInterval), f.NextRefreshAt)
+	assert.False(t, f.IsDue(now.Add(48*time.Hour)))
+
+	broken := NewBrokenFeed([]Feed{f, {ID: 2}})
+	if assert.Len(t, broken.Items, 1) {
+		assert.True(t, broken.Items[0].IsBrokenFeed())
+		assert.Equal(t, f.ID, broken.Items[0].FeedID)
+	}
+
+	f.RecordSuccess(now)
+	assert.False(t, f.IsBroken())
+	assert.Empty(t, f.LastError)
+}
+
+func TestRecordFailureRateLimit(t *testing.T) {
+	now := time.Now()
+	base := 10 * time.Minute
+	f := Feed{FailureCount: maxFailures - 1}
+
+	// Rate limits don't count towards a dead feed.
+	for range 3 {
+		f.RecordFailure(now, base, HTTPError{StatusCode: http.StatusTooManyRequests})
+	}
+	assert.Equal(t, maxFailures-1, f.FailureCount)
+	assert.False(t, f.IsDead)
+	assert.Equal(t, http.StatusTooManyRequests, f.LastStatus)
+
+	f = Feed{}
+	err := HTTPError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 2 * time.Hour}
+	f.RecordFailure(now, base, err)
+	assert.Zero(t, f.FailureCount)
+	assert.Equal(t, now.Add(2*time.Hour), f.NextRefreshAt)
+}
+
+func TestRecordFailureGone(t *testing.T) {
+	var f Feed
+	f.RecordFailure(time.Now(), time.Minute, HTTPError{StatusCode: http.StatusGone})
+	assert.True(t, f.IsDead)
+}
@@ -1,44 +1,148 @@
 package rss
 
 import (
-	"net/http"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/mmcdole/gofeed"
 )
 
-func ParseURL(u string) (Feed, error) {
-	fp := gofeed.NewParser()
-	fp.Client = &http.Client{
-		Timeout: 10 * time.Second, //nolint:mnd // timeout
+// ErrNotModified is returned by Fetch when the server answers 304,
+// which means the feed has no changes since the last fetch.
+var ErrNotModified = errors.New("feed not modified")
+
+// errNoDocument is returned by Fetch when there is nothing to parse,
+// e.g. a directory without feed files.
+var errNoDocument = errors.New("no feed files found")
+
+// HTTPError is returned when the server answers a non 2xx status.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is how long the server asks to wait before retrying.
+	RetryAfter time.Duration
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("http error: %s", e.Status)
+}
+
+func (f *Fetcher) ParseURL(ctx context.Context, u string) (Feed, error) {
+	return f.Fetch(ctx, Feed{FeedURL: u})
+}
+
+// Fetch downloads and parses the feed of feed, from the web, local
+// files or the output of a command. The returned feed carries the
+// refresh hints of the publisher even on ErrNotModified.
+func (f *Fetcher) Fetch(ctx context.Context, feed Feed) (Feed, error) {
+	src := f.source(feed.FeedURL)
+	doc, err := src.download(ctx, feed)
+	if !feed.Request.canMove(feed.FeedURL, doc.movedTo) {
+		doc.movedTo = ""
+	}
+	if errors.Is(err, ErrNotModified) {
+		feed.MaxAge = doc.maxAge
+		feed.LastStatus = doc.status
+		feed.MovedTo = doc.movedTo
+		return feed, err
 	}
-	f, err := fp.ParseURL(u)
 	if err != nil {
-		return Feed{FeedURL: u}, err
+		return feed, err
 	}
 
-	feed := toFeed(f)
-	if feed.FeedURL == "" {
-		feed.FeedURL = u
+	if len(doc.bodies) == 0 {
+		return feed, errNoDocument
+	}
+
+	// Documents of a directory are merged into one feed.
+	var ret Feed
+	for i, body := range doc.bodies {
+		part, err := f.parse(ctx, feed, body)
+		if err != nil {
+			return feed, err
+		}
+		if i == 0 {
+			ret = part
+		} else {
+			ret.Items = append(ret.Items, part.Items...)
+		}
 	}
-	return feed, nil
+
+	// The self link of a feed is only trusted for web feeds.
+	_, isHTTP := src.(httpSource)
+	if !isHTTP || ret.FeedURL == "" || !feed.Request.canMove(feed.FeedURL, ret.FeedURL) {
+		ret.FeedURL = feed.FeedURL
+	}
+	ret.Request = feed.Request
+	ret.Filter = feed.Filter
+	ret.Scraper = feed.Scraper
+	ret.ETag = doc.etag
+	ret.LastModified = doc.lastModified
+	ret.MaxAge = doc.maxAge
+	ret.LastStatus = doc.status
+	ret.MovedTo = doc.movedTo
+	return ret, nil
+}
+
+// parse pipes body through the filter of feed when it has one, and
+// scrapes it when feed has a scraper.
+func (f *Fetcher) parse(ctx context.Context, feed Feed, body []byte) (Feed, error) {
+	if feed.Filter != "" {
+		var err error
+		body, err = f.run(ctx, feed.Filter, body)
+		if err != nil {
+			return feed, fmt.Errorf("filter: %w", err)
+		}
+	}
+
+	if !feed.Scraper.IsZero() {
+		return scrapeFeed(body, feed.FeedURL, feed.Scraper)
+	}
+
+	fp := gofeed.NewParser()
+	fp.RSSTranslator = &ttlTranslator{}
+	parsed, err := fp.Parse(bytes.NewReader(body))
+	if err != nil {
+		return feed, err
+	}
+	ret := toFeed(parsed)
+	ret.TTL = feedTTL(parsed)
+	return ret, nil
 }
 
 func toFeed(f *gofeed.Feed) Feed {
 	items := make([]FeedItem, 0, len(f.Items))
 	for _, v := range f.Items {
 		desc := strings.TrimSpace(v.Description)
-		publishedAt := time.Now()
+		var publishedAt time.Time
 		if v.PublishedParsed != nil {
 			publishedAt = *v.PublishedParsed
 		}
+		var updatedAt time.Time
+		if v.UpdatedParsed != nil {
+			updatedAt = *v.UpdatedParsed
+		}
 		items = append(items, FeedItem{
+			GUID:        itemGUID(v),
 			Title:       v.Title,
 			Description: desc,
 			Content:     v.Content,
 			Link:        v.Link,
 			PublishedAt: publishedAt,
+			UpdatedAt:   updatedAt,
+			ContentHash: contentHash(v.Title, desc, v.Content),
+			Authors:     itemAuthors(v),
+			Categories:  itemCategories(v),
+			ImageURL:    itemImage(v),
+			Enclosures:  itemEnclosures(v),
 		})
 	}
 
@@ -49,3 +153,81 @@ func toFeed(f *gofeed.Feed) Feed {
 		Items:       items,
 	}
 }
+
+func itemAuthors(v *gofeed.Item) []string {
+	var authors []string
+	for _, p := range v.Authors {
+		if p == nil {
+			continue
+		}
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			name = strings.TrimSpace(p.Email)
+		}
+		if name != "" && !slices.Contains(authors, name) {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+func itemCategories(v *gofeed.Item) []string {
+	var categories []string
+	for _, c := range v.Categories {
+		c = strings.TrimSpace(c)
+		if c != "" && !slices.Contains(categories, c) {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+// itemImage falls back to the iTunes image of podcast episodes.
+func itemImage(v *gofeed.Item) string {
+	if v.Image != nil && v.Image.URL != "" {
+		return v.Image.URL
+	}
+	if v.ITunesExt != nil {
+		return v.ITunesExt.Image
+	}
+	return ""
+}
+
+func itemEnclosures(v *gofeed.Item) []Enclosure {
+	var enclosures []Enclosure
+	for _, e := range v.Enclosures {
+		if e == nil || strings.TrimSpace(e.URL) == "" {
+			continue
+		}
+		// Length is often missing or made up, 0 means unknown.
+		length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+		enclosures = append(enclosures, Enclosure{
+			URL:    strings.TrimSpace(e.URL),
+			Type:   strings.TrimSpace(e.Type),
+			Length: max(length, 0),
+		})
+	}
+	return enclosures
+}
+
+// itemGUID identifies an item within its feed. Atom ids and JSON Feed
+// ids are translated to GUID by gofeed, items without one are keyed
+// by a hash of link and title.
+func itemGUID(v *gofeed.Item) string {
+	if guid := strings.TrimSpace(v.GUID); guid != "" {
+		return guid
+	}
+
+	sum := sha256.Sum256([]byte(v.Link + "\n" + v.Title))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentHash changes whenever the publisher edits what is shown to the user.
+func contentHash(v ...string) string {
+	h := sha256.New()
+	for _, i := range v {
+		h.Write([]byte(i))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
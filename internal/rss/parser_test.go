@@ -0,0 +1,104 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0">
+<channel>
+  <title>Test</title>
+  <link>https://example.com</link>
+  <item>
+    <title>First</title>
+    <link>https://example.com/1</link>
+    <guid>https://example.com/1</guid>
+  </item>
+</channel>
+</rss>`
+
+func newTestFetcher(t *testing.T) *Fetcher {
+	t.Helper()
+	f, err := NewFetcher(FetcherOptions{})
+	require.NoError(t, err)
+	return f
+}
+
+func TestFetchConditional(t *testing.T) {
+	const etag = `"v1"`
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer srv.Close()
+
+	f, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL})
+	require.NoError(t, err)
+	assert.Equal(t, etag, f.ETag)
+	assert.Equal(t, lastModified, f.LastModified)
+	assert.Len(t, f.Items, 1)
+
+	_, err = newTestFetcher(t).Fetch(t.Context(), f)
+	require.ErrorIs(t, err, ErrNotModified)
+}
+
+func TestItemGUID(t *testing.T) {
+	withGUID := &gofeed.Item{GUID: " tag:example.com,2025:1 ", Link: "https://example.com/1"}
+	assert.Equal(t, "tag:example.com,2025:1", itemGUID(withGUID))
+
+	a := &gofeed.Item{Title: "Title", Link: "https://example.com/1"}
+	b := &gofeed.Item{Title: "Title", Link: "https://example.com/1"}
+	c := &gofeed.Item{Title: "Other", Link: "https://example.com/1"}
+	assert.Equal(t, itemGUID(a), itemGUID(b))
+	assert.NotEqual(t, itemGUID(a), itemGUID(c))
+}
+
+func TestToFeedMeta(t *testing.T) {
+	const doc = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+  <title>Podcast</title>
+  <item>
+    <title>Episode 1</title>
+    <author>alice@example.com (Alice)</author>
+    <category>go</category>
+    <category> news </category>
+    <category>go</category>
+    <itunes:image href="https://example.com/1.png"/>
+    <enclosure url="https://example.com/1.mp3" type="audio/mpeg" length="1048576"/>
+    <enclosure url="https://example.com/1.pdf" type="application/pdf" length="unknown"/>
+  </item>
+</channel>
+</rss>`
+
+	parsed, err := gofeed.NewParser().ParseString(doc)
+	require.NoError(t, err)
+
+	items := toFeed(parsed).Items
+	require.Len(t, items, 1)
+	i := items[0]
+	assert.Equal(t, []string{"Alice"}, i.Authors)
+	assert.Equal(t, []string{"go", "news"}, i.Categories)
+	assert.Equal(t, "https://example.com/1.png", i.ImageURL)
+	assert.Equal(t, []Enclosure{
+		{URL: "https://example.com/1.mp3", Type: "audio/mpeg", Length: 1048576},
+		{URL: "https://example.com/1.pdf", Type: "application/pdf"},
+	}, i.Enclosures)
+	// Without pubDate the date is left to the first seen time.
+	assert.True(t, i.PublishedAt.IsZero())
+	assert.Equal(t, "1.0 MB", i.Enclosures[0].Size())
+	assert.Equal(t, "1.mp3", i.Enclosures[0].Name())
+}
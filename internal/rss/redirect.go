@@ -0,0 +1,64 @@
+package rss
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const maxRedirects = 10
+
+var errTooManyRedirects = errors.New("stopped after 10 redirects")
+
+// redirects follows redirects of a fetch and remembers where the feed
+// moved to. The move only counts when every hop is permanent, a feed
+// behind a temporary redirect keeps its URL.
+type redirects struct {
+	movedTo   string
+	temporary bool
+}
+
+func (r *redirects) check(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errTooManyRedirects
+	}
+
+	switch req.Response.StatusCode {
+	case http.StatusMovedPermanently, http.StatusPermanentRedirect:
+		if !r.temporary {
+			r.movedTo = req.URL.String()
+		}
+	default:
+		r.temporary = true
+		r.movedTo = ""
+	}
+	return nil
+}
+
+// canMove reports whether the feed at from may move to to, by a
+// permanent redirect or its self link. The credentials of r were
+// entered for the host of from, a feed with them stays on that host.
+func (r Request) canMove(from, to string) bool {
+	if r.IsZero() {
+		return true
+	}
+	u, err := url.Parse(from)
+	if err != nil {
+		return false
+	}
+	v, err := url.Parse(to)
+	if err != nil {
+		return false
+	}
+	return sameHost(u, v)
+}
+
+// sameHost reports whether credentials for from may be sent to to.
+// An upgrade to https keeps them safe, a downgrade doesn't.
+func sameHost(from, to *url.URL) bool {
+	if from.Scheme == "https" && to.Scheme != "https" {
+		return false
+	}
+	return strings.EqualFold(from.Host, to.Host)
+}
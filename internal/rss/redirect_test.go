@@ -0,0 +1,90 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/permanent", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/feed", http.StatusPermanentRedirect)
+	})
+	mux.HandleFunc("/temporary", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/feed", http.StatusFound)
+	})
+	mux.HandleFunc("/mixed", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/temporary", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(testRSS))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := []struct {
+		path    string
+		movedTo string
+	}{
+		{path: "/permanent", movedTo: srv.URL + "/feed"},
+		{path: "/temporary"},
+		{path: "/mixed"},
+		{path: "/feed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			f, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL + tt.path})
+			require.NoError(t, err)
+			assert.Equal(t, tt.movedTo, f.MovedTo)
+		})
+	}
+}
+
+func TestFetchKeepsCredentialsOnHost(t *testing.T) {
+	var leaked []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Token") != "" {
+			leaked = append(leaked, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(testRSS))
+	}))
+	defer other.Close()
+
+	selfRSS := strings.Replace(testRSS, "<channel>", `<channel xmlns:atom="http://www.w3.org/2005/Atom">
+  <atom:link rel="self" href="`+other.URL+`/feed"/>`, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/self", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(selfRSS))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/feed", http.StatusMovedPermanently)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	r := Request{Headers: map[string]string{"X-Token": "secret"}, Username: "u", Password: "p"}
+
+	// Without credentials the feed follows its self link.
+	f, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL + "/self"})
+	require.NoError(t, err)
+	assert.Equal(t, other.URL+"/feed", f.FeedURL)
+
+	f, err = newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL + "/self", Request: r})
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/self", f.FeedURL)
+
+	f, err = newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL + "/moved", Request: r})
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/moved", f.FeedURL)
+	assert.Empty(t, f.MovedTo)
+	assert.Empty(t, leaked)
+}
@@ -1,13 +1,117 @@
 package rss
 
+import (
+	"context"
+	"time"
+)
+
 type Repo interface {
 	AddFeed(Feed) (Feed, error)
 	AddFeeds([]Feed) ([]Feed, error)
-	InsertItems(feedID int64, items []FeedItem) ([]FeedItem, error)
+	InsertItems(ctx context.Context, feedID int64, items []FeedItem) ([]FeedItem, error)
+	// GetAllFeeds returns the feeds without their items.
 	GetAllFeeds() ([]Feed, error)
+	// GetItems returns a page of items for the list, their content
+	// is left out and the description is cut short.
+	GetItems(ctx context.Context, q ItemQuery) ([]FeedItem, error)
+	// GetItem returns the item with all its content.
+	GetItem(ctx context.Context, id int64) (FeedItem, error)
+	// GetDownloadItems returns the items with saved downloads.
+	GetDownloadItems(ctx context.Context) ([]FeedItem, error)
+	CountUnread(ctx context.Context, since time.Time) (UnreadCounts, error)
+	// SearchItems returns the items matching the words of query, best
+	// first. The last word matches as a prefix.
+	SearchItems(ctx context.Context, query string, limit int) ([]SearchResult, error)
 	DeleteFeed(id int64) error
 	ToogleRead(itemID int64) error
-	MarkAllRead(itemIDs []int64) error
+	MarkAllRead(ctx context.Context, q ItemQuery) error
 	ToogleStarred(itemID int64) error
+	MarkUpdateSeen(itemID int64) error
+	SetMarkUpdatedUnread(id int64, v bool) error
+	SetExtractFullContent(id int64, v bool) error
+	SetFullContent(ctx context.Context, itemID int64, content string) error
+	MarkPlayed(ctx context.Context, itemID int64) error
+	UpdateDownload(ctx context.Context, enclosureID int64, state DownloadState, path string) error
 	RenameFeed(id int64, name string) error
+	UpdateFeedRequest(id int64, r Request) error
+	UpdateFeedFilter(id int64, filter string) error
+	UpdateFeedRetention(id int64, r Retention) error
+	// PruneItems drops the items of a feed r doesn't keep and returns
+	// how many were dropped. They are not stored again on refresh.
+	PruneItems(ctx context.Context, feedID int64, r Retention) (int, error)
+	// Optimize keeps the database small and its queries fast.
+	Optimize(ctx context.Context) error
+	UpdateFeedURL(ctx context.Context, id int64, feedURL string) error
+	UpdateFetchState(ctx context.Context, f Feed) error
+	// Changed returns the ids of the feeds other processes wrote to
+	// since the last call, including added and deleted ones.
+	Changed(ctx context.Context) ([]int64, error)
+}
+
+// ItemQuery selects the items of a feed or a smart feed, newest first.
+type ItemQuery struct {
+	FeedID int64
+	Smart  SmartKind
+	// Since is where the today smart feed starts.
+	Since time.Time
+	// AfterDate and AfterID are the last item of the previous page,
+	// zero for the first page.
+	AfterDate time.Time
+	AfterID   int64
+	// UntilDate and UntilID are the last item of the page, the page
+	// has as many items as it takes to get there when set.
+	UntilDate time.Time
+	UntilID   int64
+	Limit     int
+}
+
+// NewItemQuery selects the first limit items of f.
+func NewItemQuery(f Feed, limit int) ItemQuery {
+	q := ItemQuery{FeedID: f.ID, Smart: f.Smart, Limit: limit}
+	if f.Smart == SmartToday {
+		q.Since = TodaySince(time.Now())
+	}
+	return q
+}
+
+// Next selects the page after last.
+func (q ItemQuery) Next(last FeedItem) ItemQuery {
+	q.AfterDate = last.Date()
+	q.AfterID = last.ID
+	q.UntilDate = time.Time{}
+	q.UntilID = 0
+	return q
+}
+
+// Until selects the page down to i, e.g. to show an item far down
+// the list.
+func (q ItemQuery) Until(i FeedItem) ItemQuery {
+	q.UntilDate = i.Date()
+	q.UntilID = i.ID
+	return q
+}
+
+// UnreadCounts is how many items are unread in each feed, by feed ID,
+// and in the smart feeds.
+type UnreadCounts struct {
+	Feeds   map[int64]int
+	Today   int
+	Unread  int
+	Starred int
+}
+
+// Apply sets the unread count of f.
+func (c UnreadCounts) Apply(f *Feed) {
+	switch f.Smart {
+	case SmartToday:
+		f.UnreadCount = c.Today
+	case SmartUnread:
+		f.UnreadCount = c.Unread
+	case SmartStarred:
+		f.UnreadCount = c.Starred
+	case SmartBroken:
+		f.UnreadCount = 0
+	default:
+		f.UnreadCount = c.Feeds[f.ID]
+	}
 }
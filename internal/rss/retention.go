@@ -0,0 +1,28 @@
+package rss
+
+// Retention limits the items kept of a feed, starred items are always
+// kept. Zero or less keeps all items.
+type Retention struct {
+	// KeepItems is how many of the latest items are kept.
+	KeepItems int
+	// KeepReadDays is how many days read items are kept.
+	KeepReadDays int
+}
+
+// Of returns the retention of f, settings f leaves at zero come from r.
+// Feeds keep all items with a negative setting, whatever r says.
+func (r Retention) Of(f Feed) Retention {
+	ret := f.Retention
+	if ret.KeepItems == 0 {
+		ret.KeepItems = r.KeepItems
+	}
+	if ret.KeepReadDays == 0 {
+		ret.KeepReadDays = r.KeepReadDays
+	}
+	return ret
+}
+
+// IsZero reports whether r keeps all items.
+func (r Retention) IsZero() bool {
+	return r.KeepItems <= 0 && r.KeepReadDays <= 0
+}
@@ -0,0 +1,21 @@
+package rss
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionOf(t *testing.T) {
+	global := Retention{KeepItems: 100, KeepReadDays: 30}
+
+	assert.Equal(t, global, global.Of(Feed{}))
+
+	f := Feed{Retention: Retention{KeepItems: 10}}
+	assert.Equal(t, Retention{KeepItems: 10, KeepReadDays: 30}, global.Of(f))
+
+	// A negative setting keeps all items of the feed.
+	f = Feed{Retention: Retention{KeepItems: -1, KeepReadDays: -1}}
+	assert.True(t, global.Of(f).IsZero())
+	assert.True(t, Retention{}.Of(Feed{}).IsZero())
+}
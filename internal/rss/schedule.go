@@ -0,0 +1,158 @@
+package rss
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	gorss "github.com/mmcdole/gofeed/rss"
+)
+
+// maxRefreshInterval caps how long a quiet feed or a publisher hint
+// can delay the next refresh.
+const maxRefreshInterval = 24 * time.Hour
+
+// IsDue reports whether f should be refreshed at t.
+// Dead feeds are only refreshed on demand.
+func (f Feed) IsDue(t time.Time) bool {
+	return !f.IsDead && !f.NextRefreshAt.After(t)
+}
+
+// Schedule sets the next refresh of f after a successful fetch.
+// Feeds are refreshed every base interval, unless the publisher asks
+// for less through TTL or Cache-Control, or the feed has been quiet
+// for a while.
+func (f *Feed) Schedule(now time.Time, base time.Duration) {
+	var quiet time.Duration
+	if last := f.lastPublishedAt(); !last.IsZero() {
+		// A feed quiet for ten days is checked about once a day.
+		quiet = now.Sub(last) / 10 //nolint:mnd // backoff factor
+	}
+
+	hint := min(max(quiet, f.TTL, f.MaxAge), maxRefreshInterval)
+	f.NextRefreshAt = now.Add(max(base, hint))
+}
+
+// ScheduleRetry sets the next refresh of f after a failed fetch.
+// The interval doubles with each failure in a row, Retry-After of
+// the server is respected.
+func (f *Feed) ScheduleRetry(now time.Time, base time.Duration, err error) {
+	d := base
+	for i := 1; i < f.FailureCount && d < maxRefreshInterval; i++ {
+		d *= 2
+	}
+	d = min(d, max(base, maxRefreshInterval))
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		d = max(d, min(httpErr.RetryAfter, maxRefreshInterval))
+	}
+	f.NextRefreshAt = now.Add(d)
+}
+
+func (f Feed) lastPublishedAt() time.Time {
+	last := f.LastPublishedAt
+	for _, i := range f.Items {
+		if d := i.Date(); d.After(last) {
+			last = d
+		}
+	}
+	return last
+}
+
+// feedTTL reads the update interval the publisher declares with
+// RSS <ttl> or the syndication module.
+func feedTTL(f *gofeed.Feed) time.Duration {
+	if v, err := strconv.Atoi(f.Custom[ttlKey]); err == nil && v > 0 {
+		return time.Duration(v) * time.Minute
+	}
+
+	sy := f.Extensions["sy"]
+	if sy == nil {
+		return 0
+	}
+
+	var period time.Duration
+	switch extValue(sy, "updatePeriod") {
+	case "hourly":
+		period = time.Hour
+	case "daily", "":
+		period = 24 * time.Hour
+	case "weekly":
+		period = 7 * 24 * time.Hour
+	case "monthly":
+		period = 30 * 24 * time.Hour
+	case "yearly":
+		period = 365 * 24 * time.Hour
+	default:
+		return 0
+	}
+
+	freq, err := strconv.Atoi(extValue(sy, "updateFrequency"))
+	if err != nil || freq <= 0 {
+		freq = 1
+	}
+	return period / time.Duration(freq)
+}
+
+func extValue(e map[string][]ext.Extension, name string) string {
+	if v := e[name]; len(v) > 0 {
+		return strings.TrimSpace(v[0].Value)
+	}
+	return ""
+}
+
+const ttlKey = "ttl"
+
+// ttlTranslator keeps the RSS <ttl>, which the default translator drops.
+type ttlTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+func (t *ttlTranslator) Translate(feed any) (*gofeed.Feed, error) {
+	f, err := t.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := feed.(*gorss.Feed); ok && v.TTL != "" {
+		if f.Custom == nil {
+			f.Custom = make(map[string]string)
+		}
+		f.Custom[ttlKey] = strings.TrimSpace(v.TTL)
+	}
+	return f, nil
+}
+
+// maxAge reads max-age of Cache-Control.
+func maxAge(h http.Header) time.Duration {
+	for _, v := range strings.Split(h.Get("Cache-Control"), ",") {
+		k, n, ok := strings.Cut(strings.TrimSpace(v), "=")
+		if !ok || !strings.EqualFold(k, "max-age") {
+			continue
+		}
+		if sec, err := strconv.Atoi(strings.Trim(n, `"`)); err == nil && sec > 0 {
+			return time.Duration(sec) * time.Second
+		}
+	}
+	return 0
+}
+
+// retryAfter reads Retry-After, which is either seconds or a date.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(sec)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
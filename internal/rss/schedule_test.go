@@ -0,0 +1,96 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule(t *testing.T) {
+	now := time.Now()
+	base := 10 * time.Minute
+
+	var f Feed
+	assert.True(t, f.IsDue(now))
+
+	f.Items = []FeedItem{{PublishedAt: now.Add(-time.Hour)}}
+	f.Schedule(now, base)
+	assert.Equal(t, now.Add(base), f.NextRefreshAt)
+	assert.False(t, f.IsDue(now))
+
+	// Quiet feeds back off.
+	f.Items = []FeedItem{{PublishedAt: now.Add(-10 * 24 * time.Hour)}}
+	f.Schedule(now, base)
+	assert.Equal(t, now.Add(24*time.Hour), f.NextRefreshAt)
+
+	f.Items = nil
+	f.TTL = time.Hour
+	f.Schedule(now, base)
+	assert.Equal(t, now.Add(time.Hour), f.NextRefreshAt)
+
+	f.ScheduleRetry(now, base, HTTPError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Hour})
+	assert.Equal(t, now.Add(2*time.Hour), f.NextRefreshAt)
+}
+
+func TestFetchRefreshHints(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		header string
+		ttl    time.Duration
+		maxAge time.Duration
+	}{
+		{
+			name: "ttl",
+			body: strings.Replace(testRSS, "<channel>", "<channel><ttl>60</ttl>", 1),
+			ttl:  time.Hour,
+		},
+		{
+			name: "syndication",
+			body: strings.Replace(strings.Replace(testRSS,
+				`<rss version="2.0">`, `<rss version="2.0" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">`, 1),
+				"<channel>", "<channel><sy:updatePeriod>daily</sy:updatePeriod><sy:updateFrequency>4</sy:updateFrequency>", 1),
+			ttl: 6 * time.Hour,
+		},
+		{
+			name:   "cache control",
+			body:   testRSS,
+			header: "public, max-age=1800",
+			maxAge: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Cache-Control", tt.header)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			f, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL})
+			require.NoError(t, err)
+			assert.Equal(t, tt.ttl, f.TTL)
+			assert.Equal(t, tt.maxAge, f.MaxAge)
+		})
+	}
+}
+
+func TestFetchRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL})
+	var httpErr HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Equal(t, 2*time.Minute, httpErr.RetryAfter)
+}
@@ -0,0 +1,161 @@
+package rss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// Scraper builds the items of a web page which has no feed. Each field
+// is a CSS selector, the ones other than Item select within an item.
+type Scraper struct {
+	Item string `json:"item"`
+	// Title is the text of the link when empty.
+	Title string `json:"title,omitempty"`
+	// Link is the first link of the item when empty.
+	Link    string `json:"link,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// IsZero reports whether the feed is a real feed rather than scraped.
+func (s Scraper) IsZero() bool {
+	return s.Item == ""
+}
+
+// Validate checks that all selectors compile.
+func (s Scraper) Validate() error {
+	if s.Item == "" {
+		return errors.New("item selector is required")
+	}
+	for _, v := range []string{s.Item, s.Title, s.Link, s.Date, s.Summary} {
+		if v == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(v); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// dateLayouts are tried in order on the date text of scraped items.
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.DateTime,
+	time.DateOnly,
+	"2006/01/02",
+	"02.01.2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// scrapeFeed builds a feed of the page at pageURL with s.
+func scrapeFeed(body []byte, pageURL string, s Scraper) (Feed, error) {
+	if err := s.Validate(); err != nil {
+		return Feed{}, err
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return Feed{}, err
+	}
+
+	var items []FeedItem
+	doc.Find(s.Item).Each(func(_ int, sel *goquery.Selection) {
+		if i, ok := scrapeItem(sel, base, s); ok {
+			items = append(items, i)
+		}
+	})
+	if len(items) == 0 {
+		return Feed{}, fmt.Errorf("no items match selector %q", s.Item)
+	}
+
+	return Feed{
+		Name:        strings.TrimSpace(doc.Find("title").First().Text()),
+		FeedURL:     pageURL,
+		HomePageURL: pageURL,
+		Items:       items,
+	}, nil
+}
+
+func scrapeItem(sel *goquery.Selection, base *url.URL, s Scraper) (FeedItem, bool) {
+	link := sel.Filter("a[href]")
+	if link.Length() == 0 {
+		link = sel.Find("a[href]").First()
+	}
+	if s.Link != "" {
+		link = sel.Find(s.Link).First()
+	}
+
+	var href string
+	if v, ok := link.Attr("href"); ok {
+		if u, err := base.Parse(strings.TrimSpace(v)); err == nil {
+			href = u.String()
+		}
+	}
+
+	title := link
+	if s.Title != "" {
+		title = sel.Find(s.Title).First()
+	}
+
+	var summary string
+	if s.Summary != "" {
+		summary, _ = sel.Find(s.Summary).First().Html()
+		summary = strings.TrimSpace(summary)
+	}
+
+	i := FeedItem{
+		Title:       strings.Join(strings.Fields(title.Text()), " "),
+		Link:        href,
+		Description: summary,
+	}
+	if i.Title == "" && i.Link == "" {
+		return i, false
+	}
+	if s.Date != "" {
+		if t, ok := scrapeDate(sel.Find(s.Date).First()); ok {
+			i.PublishedAt = t
+		}
+	}
+
+	// Items of a page have no id, the link is the most stable part.
+	i.GUID = i.Link
+	if i.GUID == "" {
+		i.GUID = contentHash(i.Title)
+	}
+	i.ContentHash = contentHash(i.Title, i.Description)
+	return i, true
+}
+
+// scrapeDate prefers the machine readable datetime of <time>.
+func scrapeDate(sel *goquery.Selection) (time.Time, bool) {
+	v, ok := sel.Attr("datetime")
+	if !ok {
+		v = sel.Text()
+	}
+	v = strings.Join(strings.Fields(v), " ")
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
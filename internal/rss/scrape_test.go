@@ -0,0 +1,56 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchScraper(t *testing.T) {
+	const page = `<html><head><title>News</title></head><body>
+<article>
+  <h2><a href="/news/1">First news</a></h2>
+  <time datetime="2024-05-01T08:00:00Z">May 1</time>
+  <p>Summary of <b>first</b>.</p>
+</article>
+<article>
+  <h2><a href="https://other.example.com/2">Second news</a></h2>
+  <span class="date">May 2, 2024</span>
+</article>
+<article></article>
+</body></html>`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	s := Scraper{Item: "article", Date: "time, .date", Summary: "p"}
+	feed, err := newTestFetcher(t).Fetch(t.Context(), Feed{FeedURL: srv.URL + "/news", Scraper: s})
+	require.NoError(t, err)
+	assert.Equal(t, "News", feed.Name)
+	assert.Equal(t, srv.URL+"/news", feed.FeedURL)
+	assert.Equal(t, s, feed.Scraper)
+	require.Len(t, feed.Items, 2)
+
+	first := feed.Items[0]
+	assert.Equal(t, "First news", first.Title)
+	assert.Equal(t, srv.URL+"/news/1", first.Link)
+	assert.Equal(t, first.Link, first.GUID)
+	assert.Equal(t, "Summary of <b>first</b>.", first.Description)
+	assert.Equal(t, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), first.PublishedAt)
+
+	second := feed.Items[1]
+	assert.Equal(t, "https://other.example.com/2", second.Link)
+	assert.Equal(t, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), second.PublishedAt)
+}
+
+func TestScraperValidate(t *testing.T) {
+	require.Error(t, Scraper{}.Validate())
+	require.Error(t, Scraper{Item: "article", Title: "h2["}.Validate())
+	require.NoError(t, Scraper{Item: "article", Title: "h2"}.Validate())
+}
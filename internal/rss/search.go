@@ -0,0 +1,46 @@
+package rss
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Matched words in SearchResult.Snippet are wrapped in MatchStart and
+// MatchEnd.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// SearchResult is an item matching a search, results are ranked best
+// first.
+type SearchResult struct {
+	// Item has the start of the description only, like the item list.
+	Item FeedItem
+	// Snippet is the text around the matches, it may be cut from HTML.
+	Snippet string
+}
+
+var (
+	// Tags the snippet starts or ends inside of.
+	leadingTag  = regexp.MustCompile(`^[^<>]*>`)
+	trailingTag = regexp.MustCompile(`<[^<>]*$`)
+	spaces      = regexp.MustCompile(`\s+`)
+)
+
+// PlainSnippet is the snippet without HTML, on one line.
+func (r SearchResult) PlainSnippet(policy *bluemonday.Policy) string {
+	v := r.Snippet
+	if strings.ContainsAny(v, "<>") {
+		v = leadingTag.ReplaceAllString(v, "")
+		v = trailingTag.ReplaceAllString(v, "")
+		v = html.UnescapeString(policy.Sanitize(v))
+	}
+	// \u200B: ZERO WIDTH SPACE, can cause width not correct
+	v = strings.ReplaceAll(v, "\u200B", "")
+	v = spaces.ReplaceAllString(v, " ")
+	return strings.TrimSpace(v)
+}
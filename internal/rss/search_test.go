@@ -0,0 +1,20 @@
+package rss
+
+import (
+	"testing"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainSnippet(t *testing.T) {
+	policy := bluemonday.StrictPolicy()
+
+	r := SearchResult{Snippet: "class=\"x\">Range over <b>" + MatchStart + "iterators" + MatchEnd +
+		"</b> &amp;\n functions <a hre"}
+	assert.Equal(t, "Range over "+MatchStart+"iterators"+MatchEnd+" & functions", r.PlainSnippet(policy))
+
+	// Text without tags is left alone.
+	r = SearchResult{Snippet: "a & " + MatchStart + "b" + MatchEnd}
+	assert.Equal(t, "a & "+MatchStart+"b"+MatchEnd, r.PlainSnippet(policy))
+}
@@ -0,0 +1,96 @@
+package rss
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// document is a feed document as downloaded by a source.
+type document struct {
+	// bodies has one document, or one per file of a directory.
+	bodies       [][]byte
+	etag         string
+	lastModified string
+	maxAge       time.Duration
+	status       int
+	movedTo      string
+}
+
+// source downloads the document of a feed. When the document has not
+// changed since the last download, ErrNotModified is returned along
+// with the refresh hints of the document.
+type source interface {
+	download(ctx context.Context, feed Feed) (document, error)
+}
+
+// source picks how the feed at feedURL is downloaded.
+func (f *Fetcher) source(feedURL string) source {
+	switch {
+	case strings.HasPrefix(feedURL, execPrefix):
+		return commandSource{fetcher: f}
+	case isFilePath(feedURL):
+		return fileSource{fetcher: f}
+	default:
+		return httpSource{fetcher: f}
+	}
+}
+
+// IsHTTPURL reports whether the feed at v is downloaded over HTTP,
+// only those have a web page to discover feeds from.
+func IsHTTPURL(v string) bool {
+	return !strings.HasPrefix(v, execPrefix) && !isFilePath(v)
+}
+
+type httpSource struct {
+	fetcher *Fetcher
+}
+
+// download sends the ETag and Last-Modified of the previous download
+// as validators, so an unchanged feed costs a 304 response instead
+// of the whole document.
+func (s httpSource) download(ctx context.Context, feed Feed) (document, error) {
+	header := http.Header{}
+	if feed.ETag != "" {
+		header.Set("If-None-Match", feed.ETag)
+	}
+	if feed.LastModified != "" {
+		header.Set("If-Modified-Since", feed.LastModified)
+	}
+
+	var r redirects
+	resp, err := s.fetcher.get(ctx, feed.FeedURL, feed.Request, header, &r)
+	if err != nil {
+		return document{}, err
+	}
+	defer resp.Body.Close()
+
+	doc := document{
+		maxAge:  maxAge(resp.Header),
+		status:  resp.StatusCode,
+		movedTo: r.movedTo,
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return doc, ErrNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return doc, HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: retryAfter(resp.Header, time.Now()),
+		}
+	}
+
+	// Read the body first, the parser hides read errors of a cut body.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return doc, err
+	}
+	doc.bodies = [][]byte{body}
+	doc.etag = resp.Header.Get("ETag")
+	doc.lastModified = resp.Header.Get("Last-Modified")
+	return doc, nil
+}
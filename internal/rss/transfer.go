@@ -0,0 +1,101 @@
+package rss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Transfer is the body of a file download.
+type Transfer struct {
+	Body io.ReadCloser
+	// Offset is where Body starts in the file, 0 when the server
+	// ignored the range and sends the whole file.
+	Offset int64
+	// Total is the size of the whole file, -1 when unknown.
+	Total int64
+}
+
+// Transfer requests the file at u from offset on with a Range request,
+// with the overrides of the request r of its feed.
+// Unlike feeds, files have neither a size limit nor a timeout, the
+// audio of a podcast is much larger than its feed.
+func (f *Fetcher) Transfer(ctx context.Context, u string, offset int64, r Request) (Transfer, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Transfer{}, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	r.apply(req)
+
+	client := *f.client
+	client.Timeout = 0
+	client.CheckRedirect = checkRedirect(r, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		return Transfer{}, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return Transfer{Body: resp.Body, Total: resp.ContentLength}, nil
+	case http.StatusPartialContent:
+		start, total, ok := contentRange(resp.Header.Get("Content-Range"))
+		if !ok {
+			resp.Body.Close()
+			return Transfer{}, fmt.Errorf("invalid content range: %q", resp.Header.Get("Content-Range"))
+		}
+		return Transfer{Body: resp.Body, Offset: start, Total: total}, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The range starts at the end, the file is complete.
+		resp.Body.Close()
+		if _, total, ok := contentRange(resp.Header.Get("Content-Range")); ok && total == offset {
+			return Transfer{Body: http.NoBody, Offset: offset, Total: total}, nil
+		}
+	default:
+		resp.Body.Close()
+	}
+
+	return Transfer{}, HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+}
+
+// contentRange parses "bytes 100-199/200" and "bytes */200", the total
+// is -1 for "bytes 100-199/*".
+func contentRange(v string) (int64, int64, bool) {
+	v, ok := strings.CutPrefix(strings.TrimSpace(v), "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	r, size, ok := strings.Cut(v, "/")
+	if !ok {
+		return 0, 0, false
+	}
+
+	total := int64(-1)
+	if size != "*" {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total = n
+	}
+
+	if r == "*" {
+		return 0, total, true
+	}
+	first, _, ok := strings.Cut(r, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
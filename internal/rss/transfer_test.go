@@ -0,0 +1,67 @@
+package rss
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer(t *testing.T) {
+	content := []byte("0123456789")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "episode.mp3", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		offset int64
+		want   string
+	}{
+		{offset: 0, want: "0123456789"},
+		{offset: 4, want: "456789"},
+		{offset: 10, want: ""},
+	}
+
+	for _, tt := range tests {
+		tr, err := newTestFetcher(t).Transfer(t.Context(), srv.URL, tt.offset, Request{})
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(tr.Body)
+		require.NoError(t, err)
+		tr.Body.Close()
+
+		assert.Equal(t, tt.offset, tr.Offset)
+		assert.Equal(t, int64(len(content)), tr.Total)
+		assert.Equal(t, tt.want, string(b))
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	tests := []struct {
+		v     string
+		start int64
+		total int64
+		ok    bool
+	}{
+		{v: "bytes 100-199/200", start: 100, total: 200, ok: true},
+		{v: "bytes */200", total: 200, ok: true},
+		{v: "bytes 100-199/*", start: 100, total: -1, ok: true},
+		{v: "items 1-2/3"},
+		{v: "bytes 100/200"},
+	}
+
+	for _, tt := range tests {
+		start, total, ok := contentRange(tt.v)
+		assert.Equal(t, tt.ok, ok, tt.v)
+		if tt.ok {
+			assert.Equal(t, tt.start, start, tt.v)
+			assert.Equal(t, tt.total, total, tt.v)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN etag TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed ADD COLUMN last_modified TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN etag;
+ALTER TABLE feed DROP COLUMN last_modified;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE item ADD COLUMN guid TEXT NOT NULL DEFAULT '';
+
+-- Items saved before guid existed are keyed by link,
+-- repeated links get a key of their own to keep them.
+UPDATE item SET guid = link
+WHERE id IN (SELECT MIN(id) FROM item GROUP BY feed_id, link);
+UPDATE item SET guid = 'rssx:' || id WHERE guid = '';
+
+CREATE UNIQUE INDEX item_feed_id_guid ON item (feed_id, guid);
+
+-- +goose Down
+DROP INDEX item_feed_id_guid;
+ALTER TABLE item DROP COLUMN guid;
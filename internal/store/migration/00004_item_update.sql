@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE item ADD COLUMN updated_at INTEGER;
+ALTER TABLE item ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE item ADD COLUMN is_updated BOOLEAN NOT NULL DEFAULT FALSE;
+-- Content of the version the user has read, kept until the update is seen.
+ALTER TABLE item ADD COLUMN read_content TEXT;
+
+ALTER TABLE feed ADD COLUMN mark_updated_unread BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE item DROP COLUMN updated_at;
+ALTER TABLE item DROP COLUMN content_hash;
+ALTER TABLE item DROP COLUMN is_updated;
+ALTER TABLE item DROP COLUMN read_content;
+ALTER TABLE feed DROP COLUMN mark_updated_unread;
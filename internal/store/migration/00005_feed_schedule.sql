@@ -0,0 +1,8 @@
+-- +goose Up
+-- Update interval declared by the publisher, in seconds.
+ALTER TABLE feed ADD COLUMN ttl INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN next_refresh_at INTEGER;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN ttl;
+ALTER TABLE feed DROP COLUMN next_refresh_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE feed ADD COLUMN last_checked_at INTEGER;
+ALTER TABLE feed ADD COLUMN last_success_at INTEGER;
+ALTER TABLE feed ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+-- Failed refreshes in a row.
+ALTER TABLE feed ADD COLUMN failure_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN last_status INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN is_dead BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN last_checked_at;
+ALTER TABLE feed DROP COLUMN last_success_at;
+ALTER TABLE feed DROP COLUMN last_error;
+ALTER TABLE feed DROP COLUMN failure_count;
+ALTER TABLE feed DROP COLUMN last_status;
+ALTER TABLE feed DROP COLUMN is_dead;
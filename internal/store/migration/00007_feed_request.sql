@@ -0,0 +1,12 @@
+-- +goose Up
+-- Per feed overrides of HTTP requests, headers are a JSON object.
+ALTER TABLE feed ADD COLUMN headers TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE feed ADD COLUMN cookie TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed ADD COLUMN username TEXT NOT NULL DEFAULT '';
+ALTER TABLE feed ADD COLUMN password TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN headers;
+ALTER TABLE feed DROP COLUMN cookie;
+ALTER TABLE feed DROP COLUMN username;
+ALTER TABLE feed DROP COLUMN password;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Article extracted from the item link.
+ALTER TABLE item ADD COLUMN full_content TEXT;
+ALTER TABLE feed ADD COLUMN extract_full_content BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE item DROP COLUMN full_content;
+ALTER TABLE feed DROP COLUMN extract_full_content;
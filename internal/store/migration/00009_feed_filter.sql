@@ -0,0 +1,6 @@
+-- +goose Up
+-- Command the downloaded feed is piped through before parsing.
+ALTER TABLE feed ADD COLUMN filter TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN filter;
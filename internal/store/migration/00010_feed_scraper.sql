@@ -0,0 +1,6 @@
+-- +goose Up
+-- CSS selectors of feeds scraped from web pages, a JSON object.
+ALTER TABLE feed ADD COLUMN scraper TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE feed DROP COLUMN scraper;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Authors and categories are JSON arrays.
+ALTER TABLE item ADD COLUMN authors TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE item ADD COLUMN categories TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE item ADD COLUMN image_url TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE enclosure (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  item_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  type TEXT NOT NULL DEFAULT '',
+  length INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX enclosure_item_id_url ON enclosure (item_id, url);
+
+-- +goose Down
+DROP INDEX enclosure_item_id_url;
+DROP TABLE enclosure;
+ALTER TABLE item DROP COLUMN authors;
+ALTER TABLE item DROP COLUMN categories;
+ALTER TABLE item DROP COLUMN image_url;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE enclosure ADD COLUMN download_state TEXT NOT NULL DEFAULT '';
+ALTER TABLE enclosure ADD COLUMN download_path TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE enclosure DROP COLUMN download_state;
+ALTER TABLE enclosure DROP COLUMN download_path;
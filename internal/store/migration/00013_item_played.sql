@@ -0,0 +1,6 @@
+-- +goose Up
+-- Set when the media of the item was played to the end.
+ALTER TABLE item ADD COLUMN is_played BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE item DROP COLUMN is_played;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- When the item was first seen. Items without a publish date got the
+-- time they were fetched as published_at, which is the best guess.
+ALTER TABLE item ADD COLUMN fetched_at INTEGER;
+UPDATE item SET fetched_at = published_at;
+
+-- +goose Down
+ALTER TABLE item DROP COLUMN fetched_at;
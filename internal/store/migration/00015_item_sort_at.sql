@@ -0,0 +1,19 @@
+-- +goose Up
+-- The date items are listed by, see rss.FeedItem.Date. Pages of the
+-- item list and the smart feeds are answered by the indexes below.
+ALTER TABLE item ADD COLUMN sort_at INTEGER
+  GENERATED ALWAYS AS (COALESCE(published_at, updated_at, fetched_at, 0)) VIRTUAL;
+
+CREATE INDEX item_feed_id_sort_at ON item (feed_id, sort_at);
+CREATE INDEX item_sort_at ON item (sort_at);
+CREATE INDEX item_unread_sort_at ON item (sort_at) WHERE is_read = FALSE;
+CREATE INDEX item_unread_feed_id ON item (feed_id) WHERE is_read = FALSE;
+CREATE INDEX item_starred_sort_at ON item (sort_at) WHERE is_starred = TRUE;
+
+-- +goose Down
+DROP INDEX item_starred_sort_at;
+DROP INDEX item_unread_feed_id;
+DROP INDEX item_unread_sort_at;
+DROP INDEX item_sort_at;
+DROP INDEX item_feed_id_sort_at;
+ALTER TABLE item DROP COLUMN sort_at;
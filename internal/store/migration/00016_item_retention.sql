@@ -0,0 +1,18 @@
+-- +goose Up
+-- Retention of a feed, zero uses the global setting and less than
+-- zero keeps all items.
+ALTER TABLE feed ADD COLUMN keep_items INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feed ADD COLUMN keep_read_days INTEGER NOT NULL DEFAULT 0;
+
+-- GUIDs of pruned items, so refresh doesn't store them again.
+CREATE TABLE tombstone (
+  feed_id INTEGER NOT NULL,
+  guid TEXT NOT NULL,
+  pruned_at INTEGER NOT NULL,
+  PRIMARY KEY (feed_id, guid)
+);
+
+-- +goose Down
+DROP TABLE tombstone;
+ALTER TABLE feed DROP COLUMN keep_items;
+ALTER TABLE feed DROP COLUMN keep_read_days;
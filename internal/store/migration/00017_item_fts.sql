@@ -0,0 +1,57 @@
+-- +goose Up
+-- Full-text index of items, the rowid is the id of the item. Authors and
+-- categories are indexed as words, the extracted article replaces the
+-- content like it does in the preview.
+CREATE VIRTUAL TABLE item_fts USING fts5 (
+  title, description, content, authors, categories, feed_name,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO item_fts (rowid, title, description, content, authors, categories, feed_name)
+  SELECT id, title, description, COALESCE(full_content, content),
+    (SELECT group_concat(value, ' ') FROM json_each(item.authors)),
+    (SELECT group_concat(value, ' ') FROM json_each(item.categories)),
+    (SELECT name FROM feed WHERE feed.id = item.feed_id)
+  FROM item;
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_insert AFTER INSERT ON item BEGIN
+  INSERT INTO item_fts (rowid, title, description, content, authors, categories, feed_name)
+    VALUES (new.id, new.title, new.description, COALESCE(new.full_content, new.content),
+      (SELECT group_concat(value, ' ') FROM json_each(new.authors)),
+      (SELECT group_concat(value, ' ') FROM json_each(new.categories)),
+      (SELECT name FROM feed WHERE feed.id = new.feed_id));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_update
+  AFTER UPDATE OF feed_id, title, description, content, full_content, authors, categories ON item BEGIN
+  DELETE FROM item_fts WHERE rowid = old.id;
+  INSERT INTO item_fts (rowid, title, description, content, authors, categories, feed_name)
+    VALUES (new.id, new.title, new.description, COALESCE(new.full_content, new.content),
+      (SELECT group_concat(value, ' ') FROM json_each(new.authors)),
+      (SELECT group_concat(value, ' ') FROM json_each(new.categories)),
+      (SELECT name FROM feed WHERE feed.id = new.feed_id));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_delete AFTER DELETE ON item BEGIN
+  DELETE FROM item_fts WHERE rowid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_feed_rename AFTER UPDATE OF name ON feed BEGIN
+  UPDATE item_fts SET feed_name = new.name
+    WHERE rowid IN (SELECT id FROM item WHERE feed_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER item_fts_feed_rename;
+DROP TRIGGER item_fts_delete;
+DROP TRIGGER item_fts_update;
+DROP TRIGGER item_fts_insert;
+DROP TABLE item_fts;
@@ -0,0 +1,169 @@
+-- +goose Up
+-- Feeds added twice are merged into the first one, items in both keep
+-- the read and starred state of either. Migrations run with foreign
+-- keys off, see store.New.
+CREATE TEMP TABLE feed_merge AS
+  SELECT id, (SELECT MIN(f.id) FROM feed AS f WHERE f.feed_url = feed.feed_url) AS keep_id
+  FROM feed;
+
+UPDATE item SET
+  is_read = (SELECT MAX(d.is_read) FROM item AS d JOIN feed_merge AS m ON m.id = d.feed_id
+    WHERE d.guid = item.guid AND m.keep_id = (SELECT keep_id FROM feed_merge WHERE id = item.feed_id)),
+  is_starred = (SELECT MAX(d.is_starred) FROM item AS d JOIN feed_merge AS m ON m.id = d.feed_id
+    WHERE d.guid = item.guid AND m.keep_id = (SELECT keep_id FROM feed_merge WHERE id = item.feed_id))
+WHERE feed_id IN (SELECT id FROM feed_merge
+  WHERE keep_id IN (SELECT keep_id FROM feed_merge WHERE id <> keep_id));
+
+CREATE TEMP TABLE item_merge AS
+  SELECT item.id FROM item JOIN feed_merge AS m ON m.id = item.feed_id
+  WHERE EXISTS (SELECT 1 FROM item AS d JOIN feed_merge AS dm ON dm.id = d.feed_id
+    WHERE d.guid = item.guid AND dm.keep_id = m.keep_id AND d.feed_id < item.feed_id);
+
+DELETE FROM enclosure WHERE item_id IN (SELECT id FROM item_merge);
+DELETE FROM item WHERE id IN (SELECT id FROM item_merge);
+UPDATE item SET feed_id = (SELECT keep_id FROM feed_merge WHERE id = item.feed_id)
+  WHERE feed_id IN (SELECT id FROM feed_merge WHERE id <> keep_id);
+
+INSERT INTO tombstone (feed_id, guid, pruned_at)
+  SELECT m.keep_id, t.guid, t.pruned_at FROM tombstone AS t JOIN feed_merge AS m ON m.id = t.feed_id
+  WHERE m.id <> m.keep_id
+  ON CONFLICT (feed_id, guid) DO NOTHING;
+DELETE FROM tombstone WHERE feed_id IN (SELECT id FROM feed_merge WHERE id <> keep_id);
+DELETE FROM feed WHERE id IN (SELECT id FROM feed_merge WHERE id <> keep_id);
+
+DROP TABLE item_merge;
+DROP TABLE feed_merge;
+
+CREATE UNIQUE INDEX feed_feed_url ON feed (feed_url);
+
+-- Rows left behind by deletes of older versions.
+DELETE FROM item WHERE feed_id NOT IN (SELECT id FROM feed);
+DELETE FROM enclosure WHERE item_id NOT IN (SELECT id FROM item);
+DELETE FROM tombstone WHERE feed_id NOT IN (SELECT id FROM feed);
+
+-- Columns can't get a foreign key, the tables are copied into new ones.
+-- Ids are kept, so item_fts still matches, and so are the sequences
+-- of deleted ids.
+CREATE TEMP TABLE sequence AS SELECT name, seq FROM sqlite_sequence;
+
+CREATE TABLE item_new (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  feed_id INTEGER NOT NULL REFERENCES feed (id) ON DELETE CASCADE,
+  title TEXT NOT NULL,
+  description TEXT,
+  content TEXT,
+  link TEXT NOT NULL,
+  is_read BOOLEAN NOT NULL DEFAULT FALSE,
+  is_starred BOOLEAN NOT NULL DEFAULT FALSE,
+  published_at INTEGER,
+  guid TEXT NOT NULL DEFAULT '',
+  updated_at INTEGER,
+  content_hash TEXT NOT NULL DEFAULT '',
+  is_updated BOOLEAN NOT NULL DEFAULT FALSE,
+  read_content TEXT,
+  full_content TEXT,
+  authors TEXT NOT NULL DEFAULT '[]',
+  categories TEXT NOT NULL DEFAULT '[]',
+  image_url TEXT NOT NULL DEFAULT '',
+  is_played BOOLEAN NOT NULL DEFAULT FALSE,
+  fetched_at INTEGER,
+  sort_at INTEGER GENERATED ALWAYS AS (COALESCE(published_at, updated_at, fetched_at, 0)) VIRTUAL
+);
+
+INSERT INTO item_new (id, feed_id, title, description, content, link, is_read, is_starred,
+  published_at, guid, updated_at, content_hash, is_updated, read_content, full_content,
+  authors, categories, image_url, is_played, fetched_at)
+  SELECT id, feed_id, title, description, content, link, is_read, is_starred,
+    published_at, guid, updated_at, content_hash, is_updated, read_content, full_content,
+    authors, categories, image_url, is_played, fetched_at
+  FROM item;
+
+-- Renames check triggers, this one would miss the item table.
+DROP TRIGGER item_fts_feed_rename;
+DROP TABLE item;
+ALTER TABLE item_new RENAME TO item;
+
+-- Indexes of 00015 again. Items of a feed and read or starred items
+-- are looked up by sort_at, which is published_at when there is one,
+-- the feed_id indexes answer the foreign key too.
+CREATE UNIQUE INDEX item_feed_id_guid ON item (feed_id, guid);
+CREATE INDEX item_feed_id_sort_at ON item (feed_id, sort_at);
+CREATE INDEX item_sort_at ON item (sort_at);
+CREATE INDEX item_unread_sort_at ON item (sort_at) WHERE is_read = FALSE;
+CREATE INDEX item_unread_feed_id ON item (feed_id) WHERE is_read = FALSE;
+CREATE INDEX item_starred_sort_at ON item (sort_at) WHERE is_starred = TRUE;
+
+CREATE TABLE enclosure_new (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  item_id INTEGER NOT NULL REFERENCES item (id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  type TEXT NOT NULL DEFAULT '',
+  length INTEGER NOT NULL DEFAULT 0,
+  download_state TEXT NOT NULL DEFAULT '',
+  download_path TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO enclosure_new (id, item_id, url, type, length, download_state, download_path)
+  SELECT id, item_id, url, type, length, download_state, download_path FROM enclosure;
+
+DROP TABLE enclosure;
+ALTER TABLE enclosure_new RENAME TO enclosure;
+
+CREATE UNIQUE INDEX enclosure_item_id_url ON enclosure (item_id, url);
+
+CREATE TABLE tombstone_new (
+  feed_id INTEGER NOT NULL REFERENCES feed (id) ON DELETE CASCADE,
+  guid TEXT NOT NULL,
+  pruned_at INTEGER NOT NULL,
+  PRIMARY KEY (feed_id, guid)
+);
+
+INSERT INTO tombstone_new (feed_id, guid, pruned_at) SELECT feed_id, guid, pruned_at FROM tombstone;
+
+DROP TABLE tombstone;
+ALTER TABLE tombstone_new RENAME TO tombstone;
+
+UPDATE sqlite_sequence SET seq = MAX(seq, (SELECT s.seq FROM sequence AS s WHERE s.name = sqlite_sequence.name))
+  WHERE name IN (SELECT name FROM sequence);
+DROP TABLE sequence;
+
+-- Triggers of item_fts went with the old item table.
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_feed_rename AFTER UPDATE OF name ON feed BEGIN
+  UPDATE item_fts SET feed_name = new.name
+    WHERE rowid IN (SELECT id FROM item WHERE feed_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_insert AFTER INSERT ON item BEGIN
+  INSERT INTO item_fts (rowid, title, description, content, authors, categories, feed_name)
+    VALUES (new.id, new.title, new.description, COALESCE(new.full_content, new.content),
+      (SELECT group_concat(value, ' ') FROM json_each(new.authors)),
+      (SELECT group_concat(value, ' ') FROM json_each(new.categories)),
+      (SELECT name FROM feed WHERE feed.id = new.feed_id));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_update
+  AFTER UPDATE OF feed_id, title, description, content, full_content, authors, categories ON item BEGIN
+  DELETE FROM item_fts WHERE rowid = old.id;
+  INSERT INTO item_fts (rowid, title, description, content, authors, categories, feed_name)
+    VALUES (new.id, new.title, new.description, COALESCE(new.full_content, new.content),
+      (SELECT group_concat(value, ' ') FROM json_each(new.authors)),
+      (SELECT group_concat(value, ' ') FROM json_each(new.categories)),
+      (SELECT name FROM feed WHERE feed.id = new.feed_id));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER item_fts_delete AFTER DELETE ON item BEGIN
+  DELETE FROM item_fts WHERE rowid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- Foreign keys can't be dropped without copying the tables again, they
+-- stay. Merged feeds stay merged.
+DROP INDEX feed_feed_url;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Items keyed by link in 00003 are found by the link of a feed item,
+-- until they get its guid. Items saved since can't be told apart when
+-- their guid is their link, which keys them by link anyway.
+ALTER TABLE item ADD COLUMN legacy_guid BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE item SET legacy_guid = TRUE WHERE guid = link;
+
+-- Tombstones don't know how their item was keyed, they keep matching
+-- either way.
+ALTER TABLE tombstone ADD COLUMN legacy_guid BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE tombstone SET legacy_guid = TRUE;
+
+-- +goose Down
+ALTER TABLE tombstone DROP COLUMN legacy_guid;
+ALTER TABLE item DROP COLUMN legacy_guid;
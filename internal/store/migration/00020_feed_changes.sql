@@ -0,0 +1,42 @@
+-- +goose Up
+-- changes counts writes to a feed and its items, so other processes
+-- can tell which feeds to load again, see Store.Changed.
+ALTER TABLE feed ADD COLUMN changes INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementBegin
+CREATE TRIGGER feed_changes_update AFTER UPDATE ON feed WHEN new.changes = old.changes BEGIN
+  UPDATE feed SET changes = changes + 1 WHERE id = new.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER feed_changes_item_insert AFTER INSERT ON item BEGIN
+  UPDATE feed SET changes = changes + 1 WHERE id = new.feed_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER feed_changes_item_update AFTER UPDATE ON item BEGIN
+  UPDATE feed SET changes = changes + 1 WHERE id IN (old.feed_id, new.feed_id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER feed_changes_item_delete AFTER DELETE ON item BEGIN
+  UPDATE feed SET changes = changes + 1 WHERE id = old.feed_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER feed_changes_enclosure_update AFTER UPDATE ON enclosure BEGIN
+  UPDATE feed SET changes = changes + 1 WHERE id = (SELECT feed_id FROM item WHERE id = new.item_id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER feed_changes_enclosure_update;
+DROP TRIGGER feed_changes_item_delete;
+DROP TRIGGER feed_changes_item_update;
+DROP TRIGGER feed_changes_item_insert;
+DROP TRIGGER feed_changes_update;
+ALTER TABLE feed DROP COLUMN changes;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- seen_at is when the item of a tombstone was last in its feed, the
+-- tombstone expires once the item left the feed, see insertItems.
+ALTER TABLE tombstone ADD COLUMN seen_at INTEGER NOT NULL DEFAULT 0;
+UPDATE tombstone SET seen_at = pruned_at;
+
+-- +goose Down
+ALTER TABLE tombstone DROP COLUMN seen_at;
@@ -0,0 +1,10 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Optimize frees pages left by pruned items a few at a time, a full
+-- VACUUM locks out other processes for too long.
+PRAGMA auto_vacuum = INCREMENTAL;
+VACUUM;
+
+-- +goose Down
+PRAGMA auto_vacuum = NONE;
+VACUUM;
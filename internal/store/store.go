@@ -1,11 +1,18 @@
 package store
 
 import (
+	"context"
 	"database/sql"
 	"embed"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/lakerszhy/rssx/internal/rss"
 	"github.com/pressly/goose/v3"
@@ -16,44 +23,93 @@ var migrations embed.FS
 
 var errFeedExist = errors.New("feed already exist")
 
+// closeTimeout is how long Close waits for running writes.
+const closeTimeout = 3 * time.Second
+
+var errStoreClosed = errors.New("store already closed")
+
+// busyTimeout is how long a connection waits for the locks of other
+// processes, e.g. a second rssx, before it fails with SQLITE_BUSY.
+const busyTimeout = 5 * time.Second
+
 type Store struct {
-	db     *sql.DB
-	logger *slog.Logger
+	db *sql.DB
+	// writer is the connection of all writes, so the data_version of it
+	// only changes with writes of other processes, see Changed.
+	writer      *sql.Conn
+	writeMu     sync.Mutex
+	dataVersion int64
+	// changes is the changes column of each feed at the last Changed.
+	changes map[int64]int64
+	logger  *slog.Logger
+
+	mu     sync.Mutex
+	closed bool
+	writes sync.WaitGroup
 }
 
 func New(dir string, logger *slog.Logger) (*Store, error) {
 	name := filepath.Join(dir, "rssx.db")
-	db, err := sql.Open("sqlite", name)
-	if err != nil {
+	if err := migrate(name); err != nil {
 		return nil, err
 	}
 
-	err = db.Ping()
+	// Pragmas of the DSN apply to each connection of the pool. Write
+	// transactions take the lock when they begin, a transaction that
+	// has read can't wait for it once another process wrote.
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)"+
+		"&_txlock=immediate", name, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	goose.SetBaseFS(migrations)
-	goose.SetLogger(goose.NopLogger())
-	if err = goose.SetDialect("sqlite3"); err != nil {
+	writer, err := db.Conn(context.Background())
+	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	if err = goose.Up(db, "migration"); err != nil {
+	s := &Store{
+		db:     db,
+		writer: writer,
+		logger: logger,
+	}
+	if _, err = s.Changed(context.Background()); err != nil {
+		s.Close()
 		return nil, err
 	}
+	return s, nil
+}
 
-	return &Store{
-		db:     db,
-		logger: logger,
-	}, nil
+// migrate upgrades the database with foreign keys off, so migrations
+// may copy tables like SQLite recommends, without cascading deletes.
+// It turns on WAL too, readers and the writer of other processes don't
+// block each other then.
+func migrate(name string) error {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)",
+		name, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	goose.SetBaseFS(migrations)
+	goose.SetLogger(goose.NopLogger())
+	if err = goose.SetDialect("sqlite3"); err != nil {
+		return err
+	}
+
+	return goose.Up(db, "migration")
 }
 
 func (s *Store) AddFeed(f rss.Feed) (rss.Feed, error) {
-	tx, err := s.db.Begin()
+	tx, done, err := s.begin(context.Background())
 	if err != nil {
 		return f, err
 	}
+	defer done()
 	defer func() {
 		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 			s.logger.Error("rollback add feed failed", "error", err)
@@ -65,33 +121,20 @@ func (s *Store) AddFeed(f rss.Feed) (rss.Feed, error) {
 		return f, err
 	}
 
-	itemSQL := `INSERT INTO item (feed_id, title, description, content, link, published_at) VALUES (?, ?, ?, ?, ?, ?);`
-	itemSTMT, err := tx.Prepare(itemSQL)
+	f.Items, err = s.insertItems(context.Background(), tx, f.ID, f.Items)
 	if err != nil {
 		return f, err
 	}
-	defer itemSTMT.Close()
-
-	for i := range f.Items {
-		item := f.Items[i]
-		ret, err := itemSTMT.Exec(f.ID, item.Title, item.Description, item.Content, item.Link, item.PublishedAt.Unix())
-		if err != nil {
-			return f, err
-		}
-		f.Items[i].ID, err = ret.LastInsertId()
-		if err != nil {
-			return f, err
-		}
-	}
 
 	return f, tx.Commit()
 }
 
 func (s *Store) AddFeeds(feeds []rss.Feed) ([]rss.Feed, error) {
-	tx, err := s.db.Begin()
+	tx, done, err := s.begin(context.Background())
 	if err != nil {
 		return nil, err
 	}
+	defer done()
 	defer func() {
 		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 			s.logger.Error("rollback add feeds failed", "error", err)
@@ -114,19 +157,25 @@ func (s *Store) AddFeeds(feeds []rss.Feed) ([]rss.Feed, error) {
 }
 
 func (s *Store) addFeed(tx *sql.Tx, f rss.Feed) (rss.Feed, error) {
-	exist, err := s.isFeedExist(tx, f.FeedURL)
+	// feed_url is unique, a feed added before is left as it is.
+	feedSQL := `INSERT INTO feed (name, feed_url, home_page_url, etag, last_modified,
+		headers, cookie, username, password, filter, scraper)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (feed_url) DO NOTHING;`
+	ret, err := tx.Exec(feedSQL, f.Name, f.FeedURL, f.HomePageURL, f.ETag, f.LastModified,
+		headers(f.Request.Headers), f.Request.Cookie, f.Request.Username, f.Request.Password,
+		f.Filter, scraper(f.Scraper))
 	if err != nil {
 		return f, err
 	}
-	if exist {
-		return f, errFeedExist
-	}
 
-	feedSQL := `INSERT INTO feed (name, feed_url, home_page_url) VALUES (?, ?, ?);`
-	ret, err := tx.Exec(feedSQL, f.Name, f.FeedURL, f.HomePageURL)
+	n, err := ret.RowsAffected()
 	if err != nil {
 		return f, err
 	}
+	if n == 0 {
+		return f, errFeedExist
+	}
 
 	f.ID, err = ret.LastInsertId()
 	if err != nil {
@@ -136,46 +185,246 @@ func (s *Store) addFeed(tx *sql.Tx, f rss.Feed) (rss.Feed, error) {
 	return f, err
 }
 
-func (s *Store) InsertItems(feedID int64, items []rss.FeedItem) ([]rss.FeedItem, error) {
-	tx, err := s.db.Begin()
+func (s *Store) InsertItems(ctx context.Context, feedID int64, items []rss.FeedItem) ([]rss.FeedItem, error) {
+	tx, done, err := s.begin(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer done()
 	defer func() {
 		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
-			s.logger.Error("rollback insert items failed", "error", err)
+			s.logger.ErrorContext(ctx, "rollback insert items failed", "error", err)
 		}
 	}()
 
-	itemSQL := `INSERT INTO item (feed_id, title, description, content, link, published_at) VALUES (?, ?, ?, ?, ?, ?);`
-	itemSTMT, err := tx.Prepare(itemSQL)
+	items, err = s.insertItems(ctx, tx, feedID, items)
 	if err != nil {
 		return nil, err
 	}
-	defer itemSTMT.Close()
 
-	for i := range items {
-		item := items[i]
-		var ret sql.Result
-		ret, err = itemSTMT.Exec(feedID, item.Title, item.Description,
-			item.Content, item.Link, item.PublishedAt.Unix())
-		if err != nil {
+	return items, tx.Commit()
+}
+
+// tombstoneTTL is how long a tombstone is kept after its item was last
+// in the feed, so an item the feed dropped for a while isn't stored again.
+const tombstoneTTL = 30 * 24 * time.Hour
+
+// insertItems saves new items and updates the changed ones, both are
+// returned. Items are identified by (feed_id, guid), so saving the same
+// items again is a no-op. Items stored before guid existed are keyed by
+// link, they get the guid of the item once found.
+func (s *Store) insertItems(ctx context.Context, tx *sql.Tx,
+	feedID int64, items []rss.FeedItem) ([]rss.FeedItem, error) {
+	var markUnread bool
+	feedSQL := `SELECT mark_updated_unread FROM feed WHERE id = ?;`
+	if err := tx.QueryRowContext(ctx, feedSQL, feedID).Scan(&markUnread); err != nil {
+		return nil, err
+	}
+
+	saved := make([]rss.FeedItem, 0, len(items))
+	for _, item := range items {
+		old, err := s.findItem(ctx, tx, feedID, item)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return nil, err
 		}
-		items[i].ID, err = ret.LastInsertId()
+
+		if err == nil && old.legacyGUID && item.GUID != "" {
+			itemSQL := `UPDATE item SET guid = ?, legacy_guid = FALSE WHERE id = ?;`
+			if _, err = tx.ExecContext(ctx, itemSQL, item.GUID, old.id); err != nil {
+				return nil, err
+			}
+			old.guid = item.GUID
+		}
+
+		var ok bool
+		if errors.Is(err, sql.ErrNoRows) {
+			if ok, err = s.isPruned(ctx, tx, feedID, item); ok || err != nil {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			item, ok, err = s.insertItem(ctx, tx, feedID, item)
+		} else {
+			item, ok, err = s.updateItem(ctx, tx, old, item, markUnread)
+		}
 		if err != nil {
 			return nil, err
 		}
+		if ok {
+			saved = append(saved, item)
+		}
 	}
 
-	return items, tx.Commit()
+	// Tombstones of items the feed no longer has are not needed.
+	tombstoneSQL := `DELETE FROM tombstone WHERE feed_id = ? AND seen_at < ?;`
+	expiredAt := time.Now().Add(-tombstoneTTL).Unix()
+	if _, err := tx.ExecContext(ctx, tombstoneSQL, feedID, expiredAt); err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// findItem finds the stored item by its guid, or by its link when the
+// stored item is keyed by link.
+func (s *Store) findItem(ctx context.Context, tx *sql.Tx, feedID int64, item rss.FeedItem) (feedItem, error) {
+	itemSQL := `SELECT id, guid, legacy_guid, description, content, is_read, is_starred, is_played, published_at,
+		updated_at, fetched_at, content_hash, read_content FROM item
+		WHERE feed_id = ? AND (guid = ? OR (legacy_guid AND ? <> '' AND guid = ?))
+		ORDER BY guid = ? DESC LIMIT 1;`
+	row := tx.QueryRowContext(ctx, itemSQL, feedID, item.GUID, item.Link, item.Link, item.GUID)
+
+	var i feedItem
+	err := row.Scan(&i.id, &i.guid, &i.legacyGUID, &i.description, &i.content, &i.isRead, &i.isStarred,
+		&i.isPlayed, &i.publishedAt, &i.updatedAt, &i.fetchedAt, &i.contentHash, &i.readContent)
+	return i, err
+}
+
+// isPruned reports whether item was pruned by retention, it is keyed
+// like findItem. The tombstone of item is seen again, so it's kept as
+// long as the feed has item.
+func (s *Store) isPruned(ctx context.Context, tx *sql.Tx, feedID int64, item rss.FeedItem) (bool, error) {
+	tombstoneSQL := `UPDATE tombstone SET seen_at = ?
+		WHERE feed_id = ? AND (guid = ? OR (legacy_guid AND ? <> '' AND guid = ?));`
+	ret, err := tx.ExecContext(ctx, tombstoneSQL, time.Now().Unix(), feedID, item.GUID, item.Link, item.Link)
+	if err != nil {
+		return false, err
+	}
+	n, err := ret.RowsAffected()
+	return n > 0, err
+}
+
+func (s *Store) insertItem(ctx context.Context, tx *sql.Tx,
+	feedID int64, item rss.FeedItem) (rss.FeedItem, bool, error) {
+	// Conflict only happens when another refresh saved the item first.
+	if item.FetchedAt.IsZero() {
+		item.FetchedAt = time.Now()
+	}
+
+	itemSQL := `INSERT INTO item (feed_id, guid, title, description, content, link, published_at, updated_at,
+		fetched_at, content_hash, authors, categories, image_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (feed_id, guid) DO NOTHING;`
+	ret, err := tx.ExecContext(ctx, itemSQL, feedID, item.GUID, item.Title, item.Description,
+		item.Content, item.Link, nullTime(item.PublishedAt), nullTime(item.UpdatedAt), nullTime(item.FetchedAt),
+		item.ContentHash, stringList(item.Authors), stringList(item.Categories), item.ImageURL)
+	if err != nil {
+		return item, false, err
+	}
+
+	n, err := ret.RowsAffected()
+	if err != nil || n == 0 {
+		return item, false, err
+	}
+
+	item.ID, err = ret.LastInsertId()
+	if err != nil {
+		return item, false, err
+	}
+
+	err = s.saveEnclosures(ctx, tx, &item)
+	return item, err == nil, err
+}
+
+// saveMeta keeps authors, categories, image and enclosures of a stored
+// item in sync with the feed, they don't count as an update of the item.
+func (s *Store) saveMeta(ctx context.Context, tx *sql.Tx, item *rss.FeedItem) error {
+	itemSQL := `UPDATE item SET authors = ?, categories = ?, image_url = ?
+		WHERE id = ? AND (authors <> ? OR categories <> ? OR image_url <> ?);`
+	authors, categories := stringList(item.Authors), stringList(item.Categories)
+	_, err := tx.ExecContext(ctx, itemSQL, authors, categories, item.ImageURL,
+		item.ID, authors, categories, item.ImageURL)
+	if err != nil {
+		return err
+	}
+	return s.saveEnclosures(ctx, tx, item)
+}
+
+// saveEnclosures saves the enclosures of item and drops the ones the
+// feed no longer has. Enclosures are identified by URL within an item.
+func (s *Store) saveEnclosures(ctx context.Context, tx *sql.Tx, item *rss.FeedItem) error {
+	enclosureSQL := `INSERT INTO enclosure (item_id, url, type, length) VALUES (?, ?, ?, ?)
+		ON CONFLICT (item_id, url) DO UPDATE SET type = excluded.type, length = excluded.length
+		RETURNING id;`
+	ids := make([]int64, 0, len(item.Enclosures))
+	for i := range item.Enclosures {
+		e := &item.Enclosures[i]
+		err := tx.QueryRowContext(ctx, enclosureSQL, item.ID, e.URL, e.Type, e.Length).Scan(&e.ID)
+		if err != nil {
+			return err
+		}
+		e.ItemID = item.ID
+		ids = append(ids, e.ID)
+	}
+
+	b, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	enclosureSQL = `DELETE FROM enclosure WHERE item_id = ? AND id NOT IN (SELECT value FROM json_each(?));`
+	_, err = tx.ExecContext(ctx, enclosureSQL, item.ID, string(b))
+	return err
+}
+
+// updateItem saves item over old when the publisher changed it.
+// The version the user read is kept, so preview can show what changed.
+func (s *Store) updateItem(ctx context.Context, tx *sql.Tx,
+	old feedItem, item rss.FeedItem, markUnread bool) (rss.FeedItem, bool, error) {
+	item.ID = old.id
+	if err := s.saveMeta(ctx, tx, &item); err != nil {
+		return item, false, err
+	}
+
+	// Items saved before content_hash existed can't tell what changed.
+	if old.contentHash == "" {
+		itemSQL := `UPDATE item SET content_hash = ?, updated_at = ? WHERE id = ?;`
+		_, err := tx.ExecContext(ctx, itemSQL, item.ContentHash, nullTime(item.UpdatedAt), old.id)
+		return item, false, err
+	}
+
+	isNewer := old.updatedAt.Valid && item.UpdatedAt.Unix() > old.updatedAt.Int64
+	if old.contentHash == item.ContentHash && !isNewer {
+		return item, false, nil
+	}
+
+	readContent := old.readContent
+	if !readContent.Valid && old.isRead {
+		readContent = old.content
+		if readContent.String == "" {
+			readContent = old.description
+		}
+	}
+
+	isRead := old.isRead && !markUnread
+
+	itemSQL := `UPDATE item SET title = ?, description = ?, content = ?, link = ?, updated_at = ?,
+		content_hash = ?, is_updated = TRUE, is_read = ?, read_content = ?, full_content = NULL
+		WHERE id = ?;`
+	_, err := tx.ExecContext(ctx, itemSQL, item.Title, item.Description, item.Content, item.Link,
+		nullTime(item.UpdatedAt), item.ContentHash, isRead, readContent, old.id)
+	if err != nil {
+		return item, false, err
+	}
+
+	item.ID = old.id
+	item.GUID = old.guid
+	item.IsRead = isRead
+	item.IsStarred = old.isStarred
+	item.IsPlayed = old.isPlayed
+	item.PublishedAt = fromNullTime(old.publishedAt)
+	item.FetchedAt = fromNullTime(old.fetchedAt)
+	item.IsUpdated = true
+	item.ReadContent = readContent.String
+	return item, true, nil
 }
 
 func (s *Store) DeleteFeed(id int64) error {
-	tx, err := s.db.Begin()
+	tx, done, err := s.begin(context.Background())
 	if err != nil {
 		return err
 	}
+	defer done()
 	defer func() {
 		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 			s.logger.Error("rollback delete feed failed",
@@ -183,145 +432,598 @@ func (s *Store) DeleteFeed(id int64) error {
 		}
 	}()
 
+	// Items, their enclosures and tombstones go with the feed.
 	_, err = tx.Exec(`DELETE FROM feed WHERE id = ?;`, id)
 	if err != nil {
 		return err
 	}
 
-	_, err = tx.Exec(`DELETE FROM item WHERE feed_id = ?;`, id)
-	if err != nil {
-		return err
-	}
-
 	return tx.Commit()
 }
 
 func (s *Store) GetAllFeeds() ([]rss.Feed, error) {
-	feedSQL := `SELECT id, name, feed_url, home_page_url FROM feed;`
+	feedSQL := `SELECT id, name, feed_url, home_page_url, etag, last_modified, mark_updated_unread,
+		ttl, next_refresh_at, last_checked_at, last_success_at, last_error, failure_count,
+		last_status, is_dead, headers, cookie, username, password, extract_full_content, filter, scraper,
+		keep_items, keep_read_days, (SELECT NULLIF(MAX(sort_at), 0) FROM item WHERE item.feed_id = feed.id) FROM feed;`
 	feedRows, err := s.db.Query(feedSQL)
 	if err != nil {
 		return nil, err
 	}
 	defer feedRows.Close()
 
-	var feeds []feed
+	var feeds []rss.Feed
 	for feedRows.Next() {
 		var f feed
-		if err = feedRows.Scan(&f.id, &f.name, &f.feedURL, &f.homePageURL); err != nil {
+		if err = feedRows.Scan(&f.id, &f.name, &f.feedURL, &f.homePageURL,
+			&f.etag, &f.lastModified, &f.markUpdatedUnread, &f.ttl, &f.nextRefreshAt,
+			&f.lastCheckedAt, &f.lastSuccessAt, &f.lastError, &f.failureCount,
+			&f.lastStatus, &f.isDead, &f.headers, &f.cookie, &f.username, &f.password,
+			&f.extractFullContent, &f.filter, &f.scraper, &f.keepItems, &f.keepReadDays,
+			&f.lastPublishedAt); err != nil {
 			return nil, err
 		}
-		feeds = append(feeds, f)
+		feeds = append(feeds, f.toFeed())
+	}
+
+	return feeds, feedRows.Err()
+}
+
+// listDescriptionLength is how much of the description items of the
+// list get, enough for the line under the title.
+const listDescriptionLength = 500
+
+// itemColumns are the columns scanned by scanItem, the content columns
+// are filled in by the query.
+const itemColumns = `id, feed_id, COALESCE((SELECT name FROM feed WHERE feed.id = item.feed_id), ''),
+	guid, title, link, is_read, is_starred, is_played, published_at, updated_at, fetched_at,
+	content_hash, is_updated, authors, categories, image_url, `
+
+// fullColumns are the content columns of GetItem, the item list only
+// needs the start of the description.
+const (
+	fullColumns = `description, content, read_content, full_content`
+	listColumns = `substr(description, 1, ?), NULL, NULL, NULL`
+)
+
+func (s *Store) GetItems(ctx context.Context, q rss.ItemQuery) ([]rss.FeedItem, error) {
+	where, args := itemWhere(q)
+	args = append([]any{listDescriptionLength}, args...)
+	if q.AfterID != 0 {
+		where += ` AND (sort_at, id) < (?, ?)`
+		args = append(args, sortAt(q.AfterDate), q.AfterID)
+	}
+	limit := q.Limit
+	if q.UntilID != 0 {
+		where += ` AND (sort_at, id) >= (?, ?)`
+		args = append(args, sortAt(q.UntilDate), q.UntilID)
+		// No limit, the page ends at the item.
+		limit = -1
+	}
+	args = append(args, limit)
+
+	itemSQL := `SELECT ` + itemColumns + listColumns + ` FROM item WHERE ` + where +
+		` ORDER BY sort_at DESC, id DESC LIMIT ?;`
+	return s.queryItems(ctx, itemSQL, args...)
+}
+
+func (s *Store) GetItem(ctx context.Context, id int64) (rss.FeedItem, error) {
+	itemSQL := `SELECT ` + itemColumns + fullColumns + ` FROM item WHERE id = ?;`
+	items, err := s.queryItems(ctx, itemSQL, id)
+	if err != nil {
+		return rss.FeedItem{}, err
+	}
+	if len(items) == 0 {
+		return rss.FeedItem{}, sql.ErrNoRows
+	}
+	return items[0], nil
+}
+
+func (s *Store) GetDownloadItems(ctx context.Context) ([]rss.FeedItem, error) {
+	itemSQL := `SELECT ` + itemColumns + listColumns + ` FROM item
+		WHERE id IN (SELECT item_id FROM enclosure WHERE download_state <> ?) ORDER BY id;`
+	return s.queryItems(ctx, itemSQL, listDescriptionLength, rss.DownloadNone)
+}
+
+// searchSnippetTokens is about how many words a search snippet has.
+const searchSnippetTokens = 16
+
+// searchRank weighs the columns of item_fts in bm25, a match in the
+// title counts the most.
+const searchRank = `bm25(item_fts, 10.0, 2.0, 1.0, 5.0, 5.0, 1.0)`
+
+func (s *Store) SearchItems(ctx context.Context, query string, limit int) ([]rss.SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	searchSQL := `SELECT rowid, snippet(item_fts, -1, ?, ?, '…', ?) FROM item_fts
+		WHERE item_fts MATCH ? ORDER BY ` + searchRank + ` LIMIT ?;`
+	rows, err := s.db.QueryContext(ctx, searchSQL, rss.MatchStart, rss.MatchEnd,
+		searchSnippetTokens, match, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if err = feedRows.Err(); err != nil {
+	var ids []int64
+	snippets := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var snippet sql.NullString
+		if err = rows.Scan(&id, &snippet); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		snippets[id] = snippet.String
+	}
+	if err = rows.Err(); err != nil || len(ids) == 0 {
 		return nil, err
 	}
 
-	itemSQL := `SELECT id, feed_id, title, description, content, link, is_read, is_starred, published_at FROM item`
-	itemSTMT, err := s.db.Prepare(itemSQL)
+	b, err := json.Marshal(ids)
 	if err != nil {
 		return nil, err
 	}
-	defer itemSTMT.Close()
+	itemSQL := `SELECT ` + itemColumns + listColumns + ` FROM item
+		WHERE id IN (SELECT value FROM json_each(?));`
+	items, err := s.queryItems(ctx, itemSQL, listDescriptionLength, string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	// Items come back in any order, results keep the rank.
+	byID := make(map[int64]rss.FeedItem, len(items))
+	for _, i := range items {
+		byID[i.ID] = i
+	}
+	results := make([]rss.SearchResult, 0, len(ids))
+	for _, id := range ids {
+		if i, ok := byID[id]; ok {
+			results = append(results, rss.SearchResult{Item: i, Snippet: snippets[id]})
+		}
+	}
+	return results, nil
+}
+
+// ftsQuery turns the words of query into an FTS5 query, so characters
+// of the FTS5 syntax are searched like any other. Words are all
+// required and the last one matches as a prefix, results show up while
+// it's typed.
+func ftsQuery(query string) string {
+	words := strings.Fields(query)
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	if len(words) > 0 {
+		words[len(words)-1] += "*"
+	}
+	return strings.Join(words, " ")
+}
 
-	itemRows, err := itemSTMT.Query()
+// queryItems scans the items of itemSQL and attaches their enclosures.
+func (s *Store) queryItems(ctx context.Context, itemSQL string, args ...any) ([]rss.FeedItem, error) {
+	rows, err := s.db.QueryContext(ctx, itemSQL, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer itemRows.Close()
+	defer rows.Close()
 
-	var items []feedItem
-	for itemRows.Next() {
+	var items []rss.FeedItem
+	var ids []int64
+	for rows.Next() {
 		var i feedItem
-		if err = itemRows.Scan(&i.id, &i.feedID, &i.title, &i.description, &i.content, &i.link, &i.isRead, &i.isStarred, &i.publishedAt); err != nil {
+		if err = rows.Scan(&i.id, &i.feedID, &i.feedName, &i.guid, &i.title, &i.link,
+			&i.isRead, &i.isStarred, &i.isPlayed, &i.publishedAt, &i.updatedAt, &i.fetchedAt, &i.contentHash,
+			&i.isUpdated, &i.authors, &i.categories, &i.imageURL,
+			&i.description, &i.content, &i.readContent, &i.fullContent); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, i.toItem())
+		ids = append(ids, i.id)
 	}
 
-	if err = itemRows.Err(); err != nil {
+	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	rssFeeds := make([]rss.Feed, 0, len(feeds))
-	for i := range feeds {
-		f := feeds[i].toFeed()
-		for _, j := range items {
-			if j.feedID == f.ID {
-				f.Items = append(f.Items, j.toItem())
-			}
+	enclosures, err := s.getEnclosures(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Enclosures = enclosures[items[i].ID]
+	}
+	return items, nil
+}
+
+// itemWhere is the condition of q, smart feeds are answered by the
+// partial indexes of item.
+func itemWhere(q rss.ItemQuery) (string, []any) {
+	switch q.Smart {
+	case rss.SmartToday:
+		return `sort_at > ?`, []any{sortAt(q.Since)}
+	case rss.SmartUnread:
+		return `is_read = FALSE`, nil
+	case rss.SmartStarred:
+		return `is_starred = TRUE`, nil
+	case rss.SmartBroken:
+		// Items of the broken feed are not stored.
+		return `FALSE`, nil
+	}
+	return `feed_id = ?`, []any{q.FeedID}
+}
+
+// getEnclosures returns the enclosures of items by item id.
+func (s *Store) getEnclosures(ctx context.Context, itemIDs []int64) (map[int64][]rss.Enclosure, error) {
+	b, err := json.Marshal(itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	enclosureSQL := `SELECT id, item_id, url, type, length, download_state, download_path
+		FROM enclosure WHERE item_id IN (SELECT value FROM json_each(?)) ORDER BY id;`
+	rows, err := s.db.QueryContext(ctx, enclosureSQL, string(b))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enclosures := make(map[int64][]rss.Enclosure)
+	for rows.Next() {
+		var e rss.Enclosure
+		if err = rows.Scan(&e.ID, &e.ItemID, &e.URL, &e.Type, &e.Length,
+			&e.DownloadState, &e.Path); err != nil {
+			return nil, err
+		}
+		enclosures[e.ItemID] = append(enclosures[e.ItemID], e)
+	}
+	return enclosures, rows.Err()
+}
+
+func (s *Store) CountUnread(ctx context.Context, since time.Time) (rss.UnreadCounts, error) {
+	c := rss.UnreadCounts{Feeds: make(map[int64]int)}
+
+	itemSQL := `SELECT feed_id, COUNT(*) FROM item WHERE is_read = FALSE GROUP BY feed_id;`
+	rows, err := s.db.QueryContext(ctx, itemSQL)
+	if err != nil {
+		return c, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var feedID int64
+		var n int
+		if err = rows.Scan(&feedID, &n); err != nil {
+			return c, err
 		}
-		rssFeeds = append(rssFeeds, f)
+		c.Feeds[feedID] = n
+		c.Unread += n
+	}
+	if err = rows.Err(); err != nil {
+		return c, err
 	}
 
-	return rssFeeds, nil
+	itemSQL = `SELECT
+		(SELECT COUNT(*) FROM item WHERE is_read = FALSE AND sort_at > ?),
+		(SELECT COUNT(*) FROM item WHERE is_read = FALSE AND is_starred = TRUE);`
+	err = s.db.QueryRowContext(ctx, itemSQL, sortAt(since)).Scan(&c.Today, &c.Starred)
+	return c, err
 }
 
 func (s *Store) ToogleRead(id int64) error {
 	itemSQL := `UPDATE item SET is_read = NOT is_read WHERE id = ?;`
-	_, err := s.db.Exec(itemSQL, id)
+	_, err := s.exec(context.Background(), itemSQL, id)
+	return err
+}
+
+func (s *Store) MarkAllRead(ctx context.Context, q rss.ItemQuery) error {
+	where, args := itemWhere(q)
+	itemSQL := `UPDATE item SET is_read = TRUE WHERE is_read = FALSE AND ` + where + `;`
+	_, err := s.exec(ctx, itemSQL, args...)
+	return err
+}
+
+func (s *Store) ToogleStarred(id int64) error {
+	itemSQL := `UPDATE item SET is_starred = NOT is_starred WHERE id = ?;`
+	_, err := s.exec(context.Background(), itemSQL, id)
+	return err
+}
+
+func (s *Store) MarkUpdateSeen(id int64) error {
+	itemSQL := `UPDATE item SET is_updated = FALSE, read_content = NULL WHERE id = ?;`
+	_, err := s.exec(context.Background(), itemSQL, id)
+	return err
+}
+
+func (s *Store) SetMarkUpdatedUnread(id int64, v bool) error {
+	feedSQL := `UPDATE feed SET mark_updated_unread = ? WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, v, id)
+	return err
+}
+
+func (s *Store) SetExtractFullContent(id int64, v bool) error {
+	feedSQL := `UPDATE feed SET extract_full_content = ? WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, v, id)
+	return err
+}
+
+func (s *Store) SetFullContent(ctx context.Context, itemID int64, content string) error {
+	itemSQL := `UPDATE item SET full_content = ? WHERE id = ?;`
+	_, err := s.exec(ctx, itemSQL, content, itemID)
+	return err
+}
+
+func (s *Store) MarkPlayed(ctx context.Context, itemID int64) error {
+	itemSQL := `UPDATE item SET is_played = TRUE WHERE id = ?;`
+	_, err := s.exec(ctx, itemSQL, itemID)
+	return err
+}
+
+func (s *Store) UpdateDownload(ctx context.Context, enclosureID int64, state rss.DownloadState, path string) error {
+	enclosureSQL := `UPDATE enclosure SET download_state = ?, download_path = ? WHERE id = ?;`
+	_, err := s.exec(ctx, enclosureSQL, state, path, enclosureID)
+	return err
+}
+
+func (s *Store) RenameFeed(id int64, name string) error {
+	feedSQL := `UPDATE feed SET name = ? WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, name, id)
+	return err
+}
+
+func (s *Store) UpdateFeedRequest(id int64, r rss.Request) error {
+	feedSQL := `UPDATE feed SET headers = ?, cookie = ?, username = ?, password = ? WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, headers(r.Headers), r.Cookie, r.Username, r.Password, id)
+	return err
+}
+
+// UpdateFeedFilter also drops the cache validators of the feed,
+// a feed not modified still needs to go through the new filter.
+func (s *Store) UpdateFeedFilter(id int64, filter string) error {
+	feedSQL := `UPDATE feed SET filter = ?, etag = '', last_modified = '' WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, filter, id)
 	return err
 }
 
-func (s *Store) MarkAllRead(itemIDs []int64) error {
-	if len(itemIDs) == 0 {
-		return nil
+func (s *Store) UpdateFeedRetention(id int64, r rss.Retention) error {
+	feedSQL := `UPDATE feed SET keep_items = ?, keep_read_days = ? WHERE id = ?;`
+	_, err := s.exec(context.Background(), feedSQL, r.KeepItems, r.KeepReadDays, id)
+	return err
+}
+
+// PruneItems drops the items of the feed beyond the latest r.KeepItems,
+// and read items older than r.KeepReadDays. Starred items are kept.
+// A tombstone is left for each, see isPruned.
+func (s *Store) PruneItems(ctx context.Context, feedID int64, r rss.Retention) (int, error) {
+	if r.IsZero() {
+		return 0, nil
 	}
 
-	tx, err := s.db.Begin()
+	tx, done, err := s.begin(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	defer done()
 	defer func() {
 		if err = tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
-			s.logger.Error("rollback mark all read failed", "error", err)
+			s.logger.ErrorContext(ctx, "rollback prune items failed", "feed id", feedID, "error", err)
 		}
 	}()
 
-	itemSQL := `UPDATE item SET is_read = TRUE WHERE id = ?;`
-	itemSTMT, err := tx.Prepare(itemSQL)
+	now := time.Now()
+	readBefore := sortAt(now.AddDate(0, 0, -r.KeepReadDays))
+	itemSQL := `SELECT id FROM item WHERE feed_id = ? AND is_starred = FALSE AND (
+		(? > 0 AND id NOT IN (SELECT id FROM item WHERE feed_id = ? ORDER BY sort_at DESC, id DESC LIMIT ?))
+		OR (? > 0 AND is_read = TRUE AND sort_at < ?));`
+	rows, err := tx.QueryContext(ctx, itemSQL, feedID, r.KeepItems, feedID, max(r.KeepItems, 0),
+		r.KeepReadDays, readBefore)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	b, err := json.Marshal(ids)
+	if err != nil {
+		return 0, err
+	}
+	tombstoneSQL := `INSERT INTO tombstone (feed_id, guid, pruned_at, seen_at, legacy_guid)
+		SELECT feed_id, guid, ?1, ?1, legacy_guid FROM item WHERE id IN (SELECT value FROM json_each(?2))
+		ON CONFLICT (feed_id, guid) DO NOTHING;`
+	if _, err = tx.ExecContext(ctx, tombstoneSQL, now.Unix(), string(b)); err != nil {
+		return 0, err
+	}
+	// Enclosures go with the items.
+	deleteSQL := `DELETE FROM item WHERE id IN (SELECT value FROM json_each(?));`
+	if _, err = tx.ExecContext(ctx, deleteSQL, string(b)); err != nil {
+		return 0, err
+	}
+
+	return len(ids), tx.Commit()
+}
+
+// vacuumPages is how many free pages, left by pruned items, Optimize
+// gives back at most. The database is locked while it does, other
+// processes wait for it up to busyTimeout.
+const vacuumPages = 1000
+
+// Optimize updates the statistics of the query planner, and gives some
+// free pages of the database back.
+func (s *Store) Optimize(ctx context.Context) error {
+	done, err := s.lock()
 	if err != nil {
 		return err
 	}
-	defer itemSTMT.Close()
+	defer done()
 
-	for _, i := range itemIDs {
-		_, err = itemSTMT.Exec(i)
-		if err != nil {
-			return err
+	if _, err = s.writer.ExecContext(ctx, `PRAGMA optimize;`); err != nil {
+		return err
+	}
+
+	var free int
+	if err = s.writer.QueryRowContext(ctx, `PRAGMA freelist_count;`).Scan(&free); err != nil || free == 0 {
+		return err
+	}
+
+	// Each step of incremental_vacuum frees one page, Exec only steps
+	// once.
+	s.logger.DebugContext(ctx, "vacuum database", "free pages", free)
+	rows, err := s.writer.QueryContext(ctx, fmt.Sprintf(`PRAGMA incremental_vacuum(%d);`, vacuumPages))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() { //nolint:revive // stepping frees the pages
+	}
+	return rows.Err()
+}
+
+// Changed returns the ids of the feeds other processes wrote to since
+// the last call, including added and deleted ones. Feeds the store
+// wrote to itself are only returned along with them.
+func (s *Store) Changed(ctx context.Context) ([]int64, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	var v int64
+	if err := s.writer.QueryRowContext(ctx, `PRAGMA data_version;`).Scan(&v); err != nil {
+		return nil, err
+	}
+	changes, err := s.feedChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	if s.dataVersion != 0 && v != s.dataVersion {
+		for id, n := range changes {
+			if old, ok := s.changes[id]; !ok || old != n {
+				ids = append(ids, id)
+			}
+		}
+		for id := range s.changes {
+			if _, ok := changes[id]; !ok {
+				ids = append(ids, id)
+			}
 		}
+		slices.Sort(ids)
 	}
+	s.dataVersion = v
+	s.changes = changes
+	return ids, nil
+}
 
-	return tx.Commit()
+func (s *Store) feedChanges(ctx context.Context) (map[int64]int64, error) {
+	rows, err := s.writer.QueryContext(ctx, `SELECT id, changes FROM feed;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make(map[int64]int64)
+	for rows.Next() {
+		var id, n int64
+		if err = rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		changes[id] = n
+	}
+	return changes, rows.Err()
 }
 
-func (s *Store) ToogleStarred(id int64) error {
-	itemSQL := `UPDATE item SET is_starred = NOT is_starred WHERE id = ?;`
-	_, err := s.db.Exec(itemSQL, id)
+func (s *Store) UpdateFeedURL(ctx context.Context, id int64, feedURL string) error {
+	feedSQL := `UPDATE feed SET feed_url = ? WHERE id = ?;`
+	_, err := s.exec(ctx, feedSQL, feedURL, id)
 	return err
 }
 
-func (s *Store) RenameFeed(id int64, name string) error {
-	feedSQL := `UPDATE feed SET name = ? WHERE id = ?;`
-	_, err := s.db.Exec(feedSQL, name, id)
+func (s *Store) UpdateFetchState(ctx context.Context, f rss.Feed) error {
+	feedSQL := `UPDATE feed SET etag = ?, last_modified = ?, ttl = ?, next_refresh_at = ?,
+		last_checked_at = ?, last_success_at = ?, last_error = ?, failure_count = ?,
+		last_status = ?, is_dead = ? WHERE id = ?;`
+	_, err := s.exec(ctx, feedSQL, f.ETag, f.LastModified,
+		int64(f.TTL/time.Second), nullTime(f.NextRefreshAt),
+		nullTime(f.LastCheckedAt), nullTime(f.LastSuccessAt), f.LastError, f.FailureCount,
+		f.LastStatus, f.IsDead, f.ID)
 	return err
 }
 
-func (s *Store) isFeedExist(tx *sql.Tx, feedURL string) (bool, error) {
-	feedSQL := `SELECT 1 FROM feed WHERE feed_url = ?;`
-	row, err := tx.Query(feedSQL, feedURL)
+// begin starts a write transaction, Close waits for it to finish.
+// The returned func must be called once the transaction is done.
+func (s *Store) begin(ctx context.Context) (*sql.Tx, func(), error) {
+	done, err := s.lock()
 	if err != nil {
-		return false, err
+		return nil, nil, err
 	}
-	defer row.Close()
 
-	if err = row.Err(); err != nil {
-		return false, err
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+	return tx, done, nil
+}
+
+// exec runs a write outside of a transaction, Close waits for it to
+// finish.
+func (s *Store) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	done, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	return s.writer.ExecContext(ctx, query, args...)
+}
+
+// lock registers a write and gets the writer for it, Close waits for
+// it to finish. The returned func must be called once the write is done.
+func (s *Store) lock() (func(), error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errStoreClosed
 	}
+	s.writes.Add(1)
+	s.mu.Unlock()
 
-	return row.Next(), nil
+	s.writeMu.Lock()
+	return func() {
+		s.writeMu.Unlock()
+		s.writes.Done()
+	}, nil
 }
 
+// Close waits a short time for running writes, then closes the database.
 func (s *Store) Close() error {
-	return s.db.Close()
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.writes.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(closeTimeout):
+		s.logger.Warn("close store with writes still running")
+	}
+
+	return errors.Join(s.writer.Close(), s.db.Close())
 }
@@ -0,0 +1,601 @@
+package store
+
+import (
+	"database/sql"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := New(t.TempDir(), slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestInsertItemsIsIdempotent(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	items := []rss.FeedItem{
+		{GUID: "1", Title: "One", Link: "https://example.com/same", PublishedAt: time.Now()},
+		{GUID: "2", Title: "Two", Link: "https://example.com/same", PublishedAt: time.Now()},
+	}
+
+	inserted, err := s.InsertItems(t.Context(), f.ID, items)
+	require.NoError(t, err)
+	assert.Len(t, inserted, 2)
+
+	inserted, err = s.InsertItems(t.Context(), f.ID, items)
+	require.NoError(t, err)
+	assert.Empty(t, inserted)
+
+	got, err := s.GetItems(t.Context(), rss.NewItemQuery(f, 10))
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestMigrateItemGUID(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "rssx.db"))
+	require.NoError(t, err)
+	goose.SetBaseFS(migrations)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.UpTo(db, "migration", 2))
+
+	_, err = db.Exec(`INSERT INTO feed (name, feed_url, home_page_url) VALUES ('feed', 'u', '');
+		INSERT INTO item (feed_id, title, link) VALUES (1, 'a', 'https://example.com/1');
+		INSERT INTO item (feed_id, title, link) VALUES (1, 'b', 'https://example.com/1');`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err := New(dir, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer s.Close()
+
+	// Legacy items are keyed by link, so the same link is not added again.
+	// The item found gets the guid of the feed.
+	items := []rss.FeedItem{{GUID: "tag:example.com,2025:1", Link: "https://example.com/1"}}
+	inserted, err := s.InsertItems(t.Context(), 1, items)
+	require.NoError(t, err)
+	assert.Empty(t, inserted)
+
+	got, err := s.GetItems(t.Context(), rss.ItemQuery{FeedID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.ElementsMatch(t, []string{"tag:example.com,2025:1", "rssx:2"}, []string{got[0].GUID, got[1].GUID})
+
+	inserted, err = s.InsertItems(t.Context(), 1, items)
+	require.NoError(t, err)
+	assert.Empty(t, inserted)
+}
+
+func TestInsertItemsLinkIsNotGUID(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	// The guid of one item is the link of another, they stay apart.
+	_, err = s.InsertItems(t.Context(), f.ID, []rss.FeedItem{
+		{GUID: "https://example.com/a", Title: "A", Link: "https://example.com/b"},
+	})
+	require.NoError(t, err)
+	inserted, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{
+		{GUID: "b", Title: "B", Link: "https://example.com/a"},
+	})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1)
+
+	got, err := s.GetItems(t.Context(), rss.NewItemQuery(f, 10))
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestMigrateForeignKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "rssx.db"))
+	require.NoError(t, err)
+	goose.SetBaseFS(migrations)
+	require.NoError(t, goose.SetDialect("sqlite3"))
+	require.NoError(t, goose.UpTo(db, "migration", 17))
+
+	// Feed 3 is a duplicate of feed 1, item 5 belongs to a deleted feed.
+	_, err = db.Exec(`INSERT INTO feed (id, name, feed_url, home_page_url) VALUES
+			(1, 'a', 'u', ''), (2, 'b', 'v', ''), (3, 'a again', 'u', '');
+		INSERT INTO item (id, feed_id, guid, title, link, is_read, is_starred) VALUES
+			(1, 1, 'x', 'x', '', TRUE, FALSE), (2, 2, 'x', 'x', '', FALSE, FALSE),
+			(3, 3, 'x', 'x', '', FALSE, TRUE), (4, 3, 'y', 'y', '', FALSE, FALSE),
+			(5, 9, 'z', 'z', '', FALSE, FALSE), (6, 1, 'w', 'w', '', FALSE, FALSE);
+		INSERT INTO enclosure (item_id, url) VALUES (3, 'e3'), (4, 'e4'), (5, 'e5');
+		DELETE FROM item WHERE id = 6;`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	s, err := New(dir, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer s.Close()
+
+	feeds, err := s.GetAllFeeds()
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+
+	// Items of the duplicate move over, the ones both have keep either state.
+	got, err := s.GetItems(t.Context(), rss.ItemQuery{FeedID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, int64(4), got[0].ID)
+	assert.Equal(t, int64(1), got[1].ID)
+	assert.True(t, got[1].IsRead)
+	assert.True(t, got[1].IsStarred)
+	assert.Len(t, got[0].Enclosures, 1)
+
+	var n int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM enclosure;`).Scan(&n))
+	assert.Equal(t, 1, n)
+
+	// Deleted ids are not handed out again.
+	inserted, err := s.InsertItems(t.Context(), 2, []rss.FeedItem{{GUID: "new"}})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1)
+	assert.Equal(t, int64(7), inserted[0].ID)
+
+	_, err = s.AddFeed(rss.Feed{Name: "a", FeedURL: "u"})
+	require.ErrorIs(t, err, errFeedExist)
+
+	// Deleting a feed cascades to its items and their enclosures.
+	require.NoError(t, s.DeleteFeed(1))
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM item WHERE feed_id = 1;`).Scan(&n))
+	assert.Zero(t, n)
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM enclosure;`).Scan(&n))
+	assert.Zero(t, n)
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM item_fts WHERE item_fts MATCH 'y';`).Scan(&n))
+	assert.Zero(t, n)
+}
+
+func TestInsertItemsDetectsUpdate(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetMarkUpdatedUnread(f.ID, true))
+
+	item := rss.FeedItem{GUID: "1", Title: "One", Content: "v1", ContentHash: "h1", PublishedAt: time.Now()}
+	inserted, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1)
+	require.NoError(t, s.ToogleRead(inserted[0].ID))
+	require.NoError(t, s.MarkPlayed(t.Context(), inserted[0].ID))
+
+	item.Content, item.ContentHash = "v2", "h2"
+	updated, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, inserted[0].ID, updated[0].ID)
+	assert.True(t, updated[0].IsUpdated)
+	assert.False(t, updated[0].IsRead)
+	assert.Equal(t, "v1", updated[0].ReadContent)
+	assert.True(t, updated[0].IsPlayed)
+
+	// Same content again is not an update.
+	updated, err = s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestFeedRequest(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{
+		Name:    "feed",
+		FeedURL: "https://example.com/feed",
+		Request: rss.Request{Username: "user", Password: "secret"},
+	})
+	require.NoError(t, err)
+
+	r := rss.Request{Headers: map[string]string{"X-Token": "abc"}, Cookie: "a=1"}
+	require.NoError(t, s.UpdateFeedRequest(f.ID, r))
+
+	feeds, err := s.GetAllFeeds()
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	assert.Equal(t, r, feeds[0].Request)
+}
+
+func TestFeedScraper(t *testing.T) {
+	s := newTestStore(t)
+
+	sc := rss.Scraper{Item: "article", Link: "h2 a", Date: "time"}
+	_, err := s.AddFeeds([]rss.Feed{
+		{Name: "feed", FeedURL: "https://example.com/feed"},
+		{Name: "page", FeedURL: "https://example.com/news", Scraper: sc},
+	})
+	require.NoError(t, err)
+
+	feeds, err := s.GetAllFeeds()
+	require.NoError(t, err)
+	require.Len(t, feeds, 2)
+	assert.True(t, feeds[0].Scraper.IsZero())
+	assert.Equal(t, sc, feeds[1].Scraper)
+}
+
+func TestItemMeta(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	item := rss.FeedItem{
+		GUID:        "1",
+		Title:       "Episode",
+		PublishedAt: time.Now(),
+		ContentHash: "a",
+		Authors:     []string{"Alice"},
+		Categories:  []string{"go", "news"},
+		ImageURL:    "https://example.com/1.png",
+		Enclosures: []rss.Enclosure{
+			{URL: "https://example.com/1.mp3", Type: "audio/mpeg", Length: 1024},
+			{URL: "https://example.com/1.pdf", Type: "application/pdf"},
+		},
+	}
+	inserted, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1)
+	mp3 := inserted[0].Enclosures[0]
+	assert.NotZero(t, mp3.ID)
+
+	mp3.DownloadState = rss.DownloadDone
+	mp3.Path = "/tmp/1.mp3"
+	require.NoError(t, s.UpdateDownload(t.Context(), mp3.ID, mp3.DownloadState, mp3.Path))
+
+	// Dropped enclosures are removed, kept ones keep their id and download.
+	item.Categories = []string{"go"}
+	item.Enclosures = item.Enclosures[:1]
+	_, err = s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+	require.NoError(t, err)
+
+	got, err := s.GetItem(t.Context(), inserted[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Alice"}, got.Authors)
+	assert.Equal(t, []string{"go"}, got.Categories)
+	assert.Equal(t, "https://example.com/1.png", got.ImageURL)
+	assert.Equal(t, []rss.Enclosure{mp3}, got.Enclosures)
+
+	downloads, err := s.GetDownloadItems(t.Context())
+	require.NoError(t, err)
+	require.Len(t, downloads, 1)
+	assert.Equal(t, []rss.Enclosure{mp3}, downloads[0].Enclosures)
+
+	require.NoError(t, s.DeleteFeed(f.ID))
+	var n int
+	require.NoError(t, s.db.QueryRow(`SELECT COUNT(*) FROM enclosure;`).Scan(&n))
+	assert.Zero(t, n)
+}
+
+func TestItemFetchedAt(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	inserted, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{{GUID: "1", Title: "One"}})
+	require.NoError(t, err)
+	require.Len(t, inserted, 1)
+	assert.False(t, inserted[0].FetchedAt.IsZero())
+
+	got, err := s.GetItem(t.Context(), inserted[0].ID)
+	require.NoError(t, err)
+	assert.True(t, got.PublishedAt.IsZero())
+	assert.Equal(t, inserted[0].FetchedAt.Unix(), got.FetchedAt.Unix())
+
+	feeds, err := s.GetAllFeeds()
+	require.NoError(t, err)
+	assert.Equal(t, got.FetchedAt, feeds[0].LastPublishedAt)
+}
+
+func TestGetItems(t *testing.T) {
+	s := newTestStore(t)
+
+	feeds, err := s.AddFeeds([]rss.Feed{
+		{Name: "a", FeedURL: "https://example.com/a"},
+		{Name: "b", FeedURL: "https://example.com/b"},
+	})
+	require.NoError(t, err)
+	a, b := feeds[0], feeds[1]
+
+	now := time.Now().Truncate(time.Second)
+	var items []rss.FeedItem
+	for i := range 5 {
+		items = append(items, rss.FeedItem{
+			GUID:        strconv.Itoa(i),
+			Title:       strconv.Itoa(i),
+			Content:     "content",
+			PublishedAt: now.AddDate(0, 0, -i),
+		})
+	}
+	inserted, err := s.InsertItems(t.Context(), a.ID, items)
+	require.NoError(t, err)
+	_, err = s.InsertItems(t.Context(), b.ID, items[:1])
+	require.NoError(t, err)
+	require.NoError(t, s.ToogleStarred(inserted[3].ID))
+
+	// Pages are newest first and carry no content.
+	q := rss.NewItemQuery(a, 2)
+	page, err := s.GetItems(t.Context(), q)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []string{"0", "1"}, []string{page[0].Title, page[1].Title})
+	assert.Equal(t, "a", page[0].FeedName)
+	assert.Empty(t, page[0].Content)
+
+	page, err = s.GetItems(t.Context(), q.Next(page[1]))
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, []string{"2", "3"}, []string{page[0].Title, page[1].Title})
+
+	// A page down to an item has all the items before it.
+	page, err = s.GetItems(t.Context(), q.Until(inserted[3]))
+	require.NoError(t, err)
+	assert.Len(t, page, 4)
+
+	full, err := s.GetItem(t.Context(), page[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "content", full.Content)
+
+	today, err := s.GetItems(t.Context(), rss.NewItemQuery(rss.NewTodayFeed(), 10))
+	require.NoError(t, err)
+	assert.Len(t, today, 2)
+
+	starred, err := s.GetItems(t.Context(), rss.NewItemQuery(rss.NewStarredFeed(), 10))
+	require.NoError(t, err)
+	require.Len(t, starred, 1)
+	assert.Equal(t, "3", starred[0].Title)
+
+	counts, err := s.CountUnread(t.Context(), rss.TodaySince(time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, rss.UnreadCounts{
+		Feeds:   map[int64]int{a.ID: 5, b.ID: 1},
+		Today:   2,
+		Unread:  6,
+		Starred: 1,
+	}, counts)
+
+	require.NoError(t, s.MarkAllRead(t.Context(), rss.NewItemQuery(a, 0)))
+	counts, err = s.CountUnread(t.Context(), rss.TodaySince(time.Now()))
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int{b.ID: 1}, counts.Feeds)
+	assert.Equal(t, 1, counts.Unread)
+	assert.Zero(t, counts.Starred)
+
+	unread, err := s.GetItems(t.Context(), rss.NewItemQuery(rss.NewUnreadFeed(), 10))
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, "b", unread[0].FeedName)
+}
+
+func TestPruneItems(t *testing.T) {
+	s := newTestStore(t)
+
+	f, err := s.AddFeed(rss.Feed{Name: "a", FeedURL: "https://example.com/a"})
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	var items []rss.FeedItem
+	for i := range 5 {
+		items = append(items, rss.FeedItem{
+			GUID:        strconv.Itoa(i),
+			Title:       strconv.Itoa(i),
+			PublishedAt: now.AddDate(0, 0, -i*10),
+		})
+	}
+	inserted, err := s.InsertItems(t.Context(), f.ID, items)
+	require.NoError(t, err)
+	require.NoError(t, s.ToogleStarred(inserted[4].ID))
+
+	titles := func() []string {
+		got, err := s.GetItems(t.Context(), rss.NewItemQuery(f, 10))
+		require.NoError(t, err)
+		var v []string
+		for _, i := range got {
+			v = append(v, i.Title)
+		}
+		return v
+	}
+
+	// Nothing is pruned without retention.
+	n, err := s.PruneItems(t.Context(), f.ID, rss.Retention{})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Starred items are kept beyond the latest items.
+	n, err = s.PruneItems(t.Context(), f.ID, rss.Retention{KeepItems: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"0", "1", "2", "4"}, titles())
+
+	// Only read items expire.
+	require.NoError(t, s.ToogleRead(inserted[2].ID))
+	n, err = s.PruneItems(t.Context(), f.ID, rss.Retention{KeepReadDays: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"0", "1", "4"}, titles())
+
+	// Pruned items don't come back.
+	saved, err := s.InsertItems(t.Context(), f.ID, items)
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+	assert.Equal(t, []string{"0", "1", "4"}, titles())
+
+	// Tombstones expire once their item left the feed for a while.
+	_, err = s.db.Exec(`UPDATE tombstone SET seen_at = ?;`, now.Add(-tombstoneTTL-time.Hour).Unix())
+	require.NoError(t, err)
+	saved, err = s.InsertItems(t.Context(), f.ID, items[3:4])
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+	var guids []string
+	rows, err := s.db.Query(`SELECT guid FROM tombstone WHERE feed_id = ?;`, f.ID)
+	require.NoError(t, err)
+	for rows.Next() {
+		var guid string
+		require.NoError(t, rows.Scan(&guid))
+		guids = append(guids, guid)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"3"}, guids)
+
+	// Free pages are given back a few at a time.
+	require.NoError(t, s.Optimize(t.Context()))
+	var mode, free int
+	require.NoError(t, s.db.QueryRow(`PRAGMA auto_vacuum;`).Scan(&mode))
+	assert.Equal(t, 2, mode, "incremental")
+	require.NoError(t, s.db.QueryRow(`PRAGMA freelist_count;`).Scan(&free))
+	assert.Zero(t, free)
+
+	r := rss.Retention{KeepItems: -1, KeepReadDays: 30}
+	require.NoError(t, s.UpdateFeedRetention(f.ID, r))
+	feeds, err := s.GetAllFeeds()
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	assert.Equal(t, r, feeds[0].Retention)
+}
+
+func TestSearchItems(t *testing.T) {
+	s := newTestStore(t)
+
+	feeds, err := s.AddFeeds([]rss.Feed{
+		{Name: "Go Blog", FeedURL: "https://example.com/go"},
+		{Name: "News", FeedURL: "https://example.com/news"},
+	})
+	require.NoError(t, err)
+	a, b := feeds[0], feeds[1]
+
+	_, err = s.InsertItems(t.Context(), a.ID, []rss.FeedItem{
+		{GUID: "1", Title: "Range over iterators", Content: "<p>Functions in Go 1.23</p>"},
+		{GUID: "2", Title: "Generics", Content: "<p>Type parameters and iterators</p>",
+			Authors: []string{"Jane Doe"}},
+	})
+	require.NoError(t, err)
+	_, err = s.InsertItems(t.Context(), b.ID, []rss.FeedItem{
+		{GUID: "3", Title: "Weather", Content: "Sunny", Categories: []string{"climate"}},
+	})
+	require.NoError(t, err)
+
+	search := func(query string) []string {
+		results, err := s.SearchItems(t.Context(), query, 10)
+		require.NoError(t, err)
+		var v []string
+		for _, r := range results {
+			v = append(v, r.Item.Title)
+		}
+		return v
+	}
+
+	// Titles rank first, the last word matches as a prefix.
+	assert.Equal(t, []string{"Range over iterators", "Generics"}, search("iterat"))
+	assert.Equal(t, []string{"Generics"}, search("jane"))
+	assert.Equal(t, []string{"Weather"}, search("climate"))
+	assert.Empty(t, search("   "))
+	assert.Empty(t, search(`"c++ (x)`))
+
+	results, err := s.SearchItems(t.Context(), "iterators", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Contains(t, results[0].Snippet, rss.MatchStart+"iterators"+rss.MatchEnd)
+	assert.Equal(t, "Go Blog", results[0].Item.FeedName)
+
+	// The index follows renames, updates and deletes.
+	require.NoError(t, s.RenameFeed(b.ID, "Forecast"))
+	assert.Equal(t, []string{"Weather"}, search("forecast"))
+
+	require.NoError(t, s.SetFullContent(t.Context(), results[0].Item.ID, "Full article about loops"))
+	assert.Equal(t, []string{"Range over iterators"}, search("loops"))
+
+	require.NoError(t, s.DeleteFeed(b.ID))
+	assert.Empty(t, search("weather"))
+}
+
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	a, err := New(dir, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := New(dir, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	defer b.Close()
+
+	var mode string
+	require.NoError(t, a.db.QueryRow(`PRAGMA journal_mode;`).Scan(&mode))
+	assert.Equal(t, "wal", mode)
+
+	f, err := a.AddFeed(rss.Feed{Name: "feed", FeedURL: "https://example.com/feed"})
+	require.NoError(t, err)
+
+	// Writes of a store don't count for itself.
+	changed, err := a.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{f.ID}, changed)
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// Only the feeds written to are changed, writes of b before don't
+	// count either.
+	g, err := b.AddFeed(rss.Feed{Name: "other", FeedURL: "https://example.com/other"})
+	require.NoError(t, err)
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	_, err = a.InsertItems(t.Context(), f.ID, []rss.FeedItem{{GUID: "first"}})
+	require.NoError(t, err)
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{f.ID}, changed)
+	require.NoError(t, a.RenameFeed(g.ID, "renamed"))
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{g.ID}, changed)
+	require.NoError(t, a.DeleteFeed(g.ID))
+	changed, err = b.Changed(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{g.ID}, changed)
+
+	// Both write at once, the busy one waits for the other.
+	var wg sync.WaitGroup
+	for name, s := range map[string]*Store{"a": a, "b": b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				item := rss.FeedItem{GUID: name + strconv.Itoa(i)}
+				_, err := s.InsertItems(t.Context(), f.ID, []rss.FeedItem{item})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	feeds, err := b.GetAllFeeds()
+	require.NoError(t, err)
+	require.Len(t, feeds, 1)
+	got, err := b.GetItems(t.Context(), rss.ItemQuery{FeedID: f.ID, Limit: 100})
+	require.NoError(t, err)
+	assert.Len(t, got, 41)
+}
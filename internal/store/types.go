@@ -2,48 +2,235 @@ package store
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/lakerszhy/rssx/internal/rss"
 )
 
 type feed struct {
-	id          int64
-	name        string
-	feedURL     string
-	homePageURL string
+	id                 int64
+	name               string
+	feedURL            string
+	homePageURL        string
+	etag               string
+	lastModified       string
+	markUpdatedUnread  bool
+	ttl                int64
+	nextRefreshAt      sql.NullInt64
+	lastCheckedAt      sql.NullInt64
+	lastSuccessAt      sql.NullInt64
+	lastError          string
+	failureCount       int
+	lastStatus         int
+	isDead             bool
+	headers            headers
+	cookie             string
+	username           string
+	password           string
+	extractFullContent bool
+	filter             string
+	scraper            scraper
+	keepItems          int
+	keepReadDays       int
+	lastPublishedAt    sql.NullInt64
 }
 
 func (f feed) toFeed() rss.Feed {
 	return rss.Feed{
-		ID:          f.id,
-		Name:        f.name,
-		FeedURL:     f.feedURL,
-		HomePageURL: f.homePageURL,
+		ID:                 f.id,
+		Name:               f.name,
+		FeedURL:            f.feedURL,
+		HomePageURL:        f.homePageURL,
+		ETag:               f.etag,
+		LastModified:       f.lastModified,
+		MarkUpdatedUnread:  f.markUpdatedUnread,
+		TTL:                time.Duration(f.ttl) * time.Second,
+		NextRefreshAt:      fromNullTime(f.nextRefreshAt),
+		LastCheckedAt:      fromNullTime(f.lastCheckedAt),
+		LastSuccessAt:      fromNullTime(f.lastSuccessAt),
+		LastError:          f.lastError,
+		FailureCount:       f.failureCount,
+		LastStatus:         f.lastStatus,
+		IsDead:             f.isDead,
+		ExtractFullContent: f.extractFullContent,
+		Filter:             f.filter,
+		Scraper:            rss.Scraper(f.scraper),
+		LastPublishedAt:    fromNullTime(f.lastPublishedAt),
+		Retention: rss.Retention{
+			KeepItems:    f.keepItems,
+			KeepReadDays: f.keepReadDays,
+		},
+		Request: rss.Request{
+			Headers:  f.headers,
+			Cookie:   f.cookie,
+			Username: f.username,
+			Password: f.password,
+		},
 	}
 }
 
 type feedItem struct {
 	id          int64
 	feedID      int64
+	feedName    string
+	guid        string
+	legacyGUID  bool
 	title       string
 	description sql.NullString
 	content     sql.NullString
 	link        string
 	isRead      bool
 	isStarred   bool
+	isPlayed    bool
 	publishedAt sql.NullInt64
+	updatedAt   sql.NullInt64
+	fetchedAt   sql.NullInt64
+	contentHash string
+	isUpdated   bool
+	readContent sql.NullString
+	fullContent sql.NullString
+	authors     stringList
+	categories  stringList
+	imageURL    string
 }
 
 func (i feedItem) toItem() rss.FeedItem {
 	return rss.FeedItem{
 		ID:          i.id,
+		FeedID:      i.feedID,
+		FeedName:    i.feedName,
+		GUID:        i.guid,
 		Title:       i.title,
 		Description: i.description.String,
 		Content:     i.content.String,
 		Link:        i.link,
 		IsRead:      i.isRead,
 		IsStarred:   i.isStarred,
-		PublishedAt: time.Unix(i.publishedAt.Int64, 0),
+		IsPlayed:    i.isPlayed,
+		PublishedAt: fromNullTime(i.publishedAt),
+		UpdatedAt:   fromNullTime(i.updatedAt),
+		FetchedAt:   fromNullTime(i.fetchedAt),
+		ContentHash: i.contentHash,
+		IsUpdated:   i.isUpdated,
+		ReadContent: i.readContent.String,
+		FullContent: i.fullContent.String,
+		Authors:     i.authors,
+		Categories:  i.categories,
+		ImageURL:    i.imageURL,
+	}
+}
+
+func nullTime(t time.Time) sql.NullInt64 {
+	if t.IsZero() {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.Unix(), Valid: true}
+}
+
+// sortAt is the sort_at of an item dated t.
+func sortAt(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromNullTime(v sql.NullInt64) time.Time {
+	if !v.Valid {
+		return time.Time{}
+	}
+	return time.Unix(v.Int64, 0)
+}
+
+// headers is stored as a JSON object.
+type headers map[string]string
+
+func (h headers) Value() (driver.Value, error) {
+	if h == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(h))
+	return string(b), err
+}
+
+func (h *headers) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	case nil:
+		*h = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported headers type: %T", src)
+	}
+	return json.Unmarshal(b, (*map[string]string)(h))
+}
+
+// scraper is stored as a JSON object, empty for feeds which are
+// not scraped.
+type scraper rss.Scraper
+
+func (s scraper) Value() (driver.Value, error) {
+	if rss.Scraper(s).IsZero() {
+		return "", nil
+	}
+	b, err := json.Marshal(rss.Scraper(s))
+	return string(b), err
+}
+
+func (s *scraper) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	case nil:
+	default:
+		return fmt.Errorf("unsupported scraper type: %T", src)
+	}
+	if len(b) == 0 {
+		*s = scraper{}
+		return nil
+	}
+	return json.Unmarshal(b, (*rss.Scraper)(s))
+}
+
+// stringList is stored as a JSON array.
+type stringList []string
+
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	return string(b), err
+}
+
+func (l *stringList) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported string list type: %T", src)
+	}
+	if err := json.Unmarshal(b, (*[]string)(l)); err != nil {
+		return err
+	}
+	if len(*l) == 0 {
+		*l = nil
 	}
+	return nil
 }
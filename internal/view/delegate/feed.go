@@ -35,19 +35,24 @@ func (f feed) Render(w io.Writer, m list.Model, index int, item list.Item) {
 		prompt = ">"
 	}
 
-	unreadCount := i.UnreadCount()
+	unreadCount := i.UnreadCount
 	unreadStr := ""
 	if unreadCount > 0 {
 		unreadStr = fmt.Sprintf(" [%d]", unreadCount)
 		unreadStr = style.Render(unreadStr)
 	}
 
-	nameWidth := m.Width() - lipgloss.Width(unreadStr)
+	broken := ""
+	if i.IsBroken() {
+		broken = lipgloss.NewStyle().Foreground(f.theme.Error).Render(" ⚠")
+	}
+
+	nameWidth := m.Width() - lipgloss.Width(unreadStr) - lipgloss.Width(broken)
 	name := ansi.Truncate(fmt.Sprintf("%s %s", prompt, i.Name),
 		nameWidth, "...")
 	name = style.Width(nameWidth).Render(name)
 
-	text := lipgloss.NewStyle().Render(name + unreadStr)
+	text := lipgloss.NewStyle().Render(name + broken + unreadStr)
 	fmt.Fprint(w, text)
 }
 
@@ -3,6 +3,7 @@ package delegate
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -15,6 +16,9 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 )
 
+// maxAuthorWidth keeps the description readable for long author lists.
+const maxAuthorWidth = 20
+
 type item struct {
 	theme      *config.AppTheme
 	htmlPolicy *bluemonday.Policy
@@ -59,7 +63,11 @@ func (d item) descView(i rss.FeedItem, width int, isSelected bool) string {
 		descStyle = descStyle.Foreground(d.theme.ItemDescActive)
 	}
 
-	date := i.PublishedAt.Format(time.DateOnly)
+	date := i.Date().Format(time.DateOnly)
+	if author := i.Author(); author != "" {
+		author = ansi.Truncate(author, maxAuthorWidth, "...")
+		date = fmt.Sprintf("%s · %s", author, date)
+	}
 	date = descStyle.Bold(true).Render(date)
 
 	descWidth := width - ansi.StringWidth(prompt) - ansi.StringWidth(date) - 2 //nolint:mnd // two space
@@ -86,6 +94,8 @@ func titleView(i rss.FeedItem, width int, isSelected bool, theme *config.AppThem
 	prompt := " "
 	unread := ""
 	starred := ""
+	updated := ""
+	played := ""
 	titleStyle := lipgloss.NewStyle().Foreground(theme.ItemTitle)
 
 	if !i.IsRead {
@@ -94,6 +104,12 @@ func titleView(i rss.FeedItem, width int, isSelected bool, theme *config.AppThem
 	if i.IsStarred {
 		starred = lipgloss.NewStyle().Foreground(theme.Starred).Render("⭑")
 	}
+	if i.IsUpdated {
+		updated = lipgloss.NewStyle().Foreground(theme.Updated).Render("↻")
+	}
+	if i.IsPlayed {
+		played = lipgloss.NewStyle().Foreground(theme.ItemDesc).Render("✓")
+	}
 	if isSelected {
 		prompt = view.Border.Left
 		titleStyle = titleStyle.Foreground(theme.ItemTitleActive)
@@ -103,6 +119,12 @@ func titleView(i rss.FeedItem, width int, isSelected bool, theme *config.AppThem
 	if len(unread) > 0 {
 		suffix = fmt.Sprintf("%s %s", starred, unread)
 	}
+	if len(updated) > 0 {
+		suffix = strings.TrimSpace(fmt.Sprintf("%s %s", updated, suffix))
+	}
+	if len(played) > 0 {
+		suffix = strings.TrimSpace(fmt.Sprintf("%s %s", played, suffix))
+	}
 	titleWidth := width - ansi.StringWidth(suffix)
 	if len(suffix) > 0 {
 		titleWidth--
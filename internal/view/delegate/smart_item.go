@@ -80,7 +80,7 @@ func (d SmartItem) infoView(i rss.FeedItem, width int, isSelected bool) string {
 		style = style.Foreground(d.theme.ItemDescActive)
 	}
 
-	date := i.PublishedAt.Format(time.DateOnly)
+	date := i.Date().Format(time.DateOnly)
 	date = style.Render(date)
 
 	authorWidth := width - ansi.StringWidth(prompt) - ansi.StringWidth(date) - 2 //nolint:mnd // two space
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/lakerszhy/rssx/internal/config"
 	"github.com/lakerszhy/rssx/internal/message"
 	"github.com/lakerszhy/rssx/internal/rss"
@@ -16,15 +17,22 @@ import (
 type AddFeed struct {
 	cfg        *config.App
 	repo       rss.Repo
+	fetcher    *rss.Fetcher
 	ti         textinput.Model
 	addFeedMsg message.AddFeed
+	// links are the feeds discovered on the page of feed,
+	// one is picked to add.
+	feed   rss.Feed
+	links  []rss.FeedLink
+	cursor int
 }
 
-func NewAddFeed(cfg *config.App, repo rss.Repo) tea.Model {
+func NewAddFeed(cfg *config.App, repo rss.Repo, fetcher *rss.Fetcher) tea.Model {
 	return AddFeed{
-		cfg:  cfg,
-		repo: repo,
-		ti:   newTextInput(cfg.Theme, "Feed URL"),
+		cfg:     cfg,
+		repo:    repo,
+		fetcher: fetcher,
+		ti:      newTextInput(cfg.Theme, "Feed URL, file path, or exec:command"),
 	}
 }
 
@@ -38,10 +46,16 @@ func (d AddFeed) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case message.AddFeed:
 		return d.onAddFeedMsg(msg)
+	case message.DiscoverFeeds:
+		return d.onDiscoverFeedsMsg(msg)
 	case tea.KeyMsg:
 		if key.Matches(msg, d.cfg.KeyMap.Enter) {
 			return d.onEnterKeyMsg()
 		}
+		if len(d.links) > 0 {
+			d.onLinksKeyMsg(msg)
+			return d, nil
+		}
 	}
 
 	d.ti, cmd = d.ti.Update(msg)
@@ -52,7 +66,7 @@ func (d AddFeed) onAddFeedMsg(msg message.AddFeed) (tea.Model, tea.Cmd) {
 	d.addFeedMsg = msg
 
 	var cmd tea.Cmd
-	if msg.IsInProgress() {
+	if msg.IsInProgress() || len(d.links) > 0 {
 		d.ti.Blur()
 	} else {
 		cmd = d.ti.Focus()
@@ -61,29 +75,81 @@ func (d AddFeed) onAddFeedMsg(msg message.AddFeed) (tea.Model, tea.Cmd) {
 	return d, cmd
 }
 
+func (d AddFeed) onDiscoverFeedsMsg(msg message.DiscoverFeeds) (tea.Model, tea.Cmd) {
+	d.addFeedMsg = message.NewAddFeedInitial()
+	d.feed = msg.Feed
+	d.links = msg.Links
+	d.cursor = 0
+	d.ti.Blur()
+	return d, nil
+}
+
+func (d *AddFeed) onLinksKeyMsg(msg tea.KeyMsg) {
+	if key.Matches(msg, d.cfg.KeyMap.Up) && d.cursor > 0 {
+		d.cursor--
+	}
+	if key.Matches(msg, d.cfg.KeyMap.Down) && d.cursor < len(d.links)-1 {
+		d.cursor++
+	}
+}
+
 func (d AddFeed) onEnterKeyMsg() (tea.Model, tea.Cmd) {
 	if d.addFeedMsg.IsInProgress() {
 		return d, nil
 	}
 
+	if len(d.links) > 0 {
+		f := d.feed
+		f.FeedURL = d.links[d.cursor].URL
+		return d, message.AddFeedCmd(f, d.repo, d.fetcher)
+	}
+
 	v := strings.TrimSpace(d.ti.Value())
 	if v == "" {
 		return d, nil
 	}
 
-	return d, message.AddFeedCmd(v, d.repo)
+	return d, message.AddFeedCmd(rss.ParseFeedURL(v), d.repo, d.fetcher)
 }
 
 func (d AddFeed) View() string {
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
 		inputView(d.ti, d.cfg.Theme),
+		d.linksView(),
 		fmt.Sprintf("%s\n", d.msgView()),
 		actionsView(d.cfg.Theme, false),
 	)
 	return render("Add Feed", content, d.cfg.Theme)
 }
 
+func (d AddFeed) linksView() string {
+	if len(d.links) == 0 {
+		return ""
+	}
+
+	width := dialogWidth - 4 //nolint:mnd // horizontal padding
+	lines := make([]string, 0, len(d.links)+1)
+	lines = append(lines, lipgloss.NewStyle().Foreground(d.cfg.Theme.DialogMsg).
+		Render("Found several feeds, pick one:"))
+	for i, l := range d.links {
+		prompt := " "
+		style := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitle)
+		if i == d.cursor {
+			prompt = ">"
+			style = style.Foreground(d.cfg.Theme.ItemTitleActive)
+		}
+
+		name := l.URL
+		if l.Title != "" {
+			name = fmt.Sprintf("%s (%s)", l.Title, l.URL)
+		}
+		lines = append(lines, style.Render(
+			ansi.Truncate(fmt.Sprintf("%s %s", prompt, name), width, "...")))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
 func (d AddFeed) msgView() string {
 	style := lipgloss.NewStyle().Width(dialogWidth)
 	if d.addFeedMsg.IsInProgress() {
@@ -0,0 +1,155 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+const (
+	inputPageURL = iota
+	inputItem
+	inputTitle
+	inputLink
+	inputDate
+	inputSummary
+)
+
+type AddScrapedFeed struct {
+	cfg        *config.App
+	repo       rss.Repo
+	fetcher    *rss.Fetcher
+	inputs     []textinput.Model
+	focus      int
+	err        error
+	addFeedMsg message.AddFeed
+}
+
+func NewAddScrapedFeed(cfg *config.App, repo rss.Repo, fetcher *rss.Fetcher) tea.Model {
+	inputs := []textinput.Model{
+		newTextInput(cfg.Theme, "Page URL"),
+		newTextInput(cfg.Theme, "Item selector, e.g. article"),
+		newTextInput(cfg.Theme, "Title selector, text of link by default"),
+		newTextInput(cfg.Theme, "Link selector, first link by default"),
+		newTextInput(cfg.Theme, "Date selector, e.g. time"),
+		newTextInput(cfg.Theme, "Summary selector, e.g. p"),
+	}
+	for i := range inputs {
+		if i != inputPageURL {
+			inputs[i].Blur()
+		}
+	}
+
+	return AddScrapedFeed{
+		cfg:     cfg,
+		repo:    repo,
+		fetcher: fetcher,
+		inputs:  inputs,
+	}
+}
+
+func (d AddScrapedFeed) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (d AddScrapedFeed) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case message.AddFeed:
+		return d.onAddFeedMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, d.cfg.KeyMap.Enter) {
+			return d.onEnterKeyMsg()
+		}
+		if key.Matches(msg, nextInputKey) {
+			return d, d.focusInput((d.focus + 1) % len(d.inputs))
+		}
+		if key.Matches(msg, prevInputKey) {
+			return d, d.focusInput((d.focus + len(d.inputs) - 1) % len(d.inputs))
+		}
+	}
+
+	d.inputs[d.focus], cmd = d.inputs[d.focus].Update(msg)
+	return d, cmd
+}
+
+func (d *AddScrapedFeed) focusInput(i int) tea.Cmd {
+	d.inputs[d.focus].Blur()
+	d.focus = i
+	return d.inputs[d.focus].Focus()
+}
+
+func (d AddScrapedFeed) onAddFeedMsg(msg message.AddFeed) (tea.Model, tea.Cmd) {
+	d.addFeedMsg = msg
+	d.err = nil
+
+	var cmd tea.Cmd
+	if msg.IsInProgress() {
+		d.inputs[d.focus].Blur()
+	} else {
+		cmd = d.inputs[d.focus].Focus()
+	}
+
+	return d, cmd
+}
+
+func (d AddScrapedFeed) onEnterKeyMsg() (tea.Model, tea.Cmd) {
+	if d.addFeedMsg.IsInProgress() {
+		return d, nil
+	}
+
+	v := strings.TrimSpace(d.inputs[inputPageURL].Value())
+	if v == "" {
+		return d, nil
+	}
+
+	s := rss.Scraper{
+		Item:    strings.TrimSpace(d.inputs[inputItem].Value()),
+		Title:   strings.TrimSpace(d.inputs[inputTitle].Value()),
+		Link:    strings.TrimSpace(d.inputs[inputLink].Value()),
+		Date:    strings.TrimSpace(d.inputs[inputDate].Value()),
+		Summary: strings.TrimSpace(d.inputs[inputSummary].Value()),
+	}
+	if err := s.Validate(); err != nil {
+		d.err = err
+		return d, nil
+	}
+
+	f := rss.ParseFeedURL(v)
+	f.Scraper = s
+	return d, message.AddFeedCmd(f, d.repo, d.fetcher)
+}
+
+func (d AddScrapedFeed) View() string {
+	views := make([]string, 0, len(d.inputs)+2) //nolint:mnd // msg + actions
+	for _, i := range d.inputs {
+		views = append(views, inputView(i, d.cfg.Theme))
+	}
+	views = append(views, fmt.Sprintf("%s\n", d.msgView()))
+	views = append(views, actionsView(d.cfg.Theme, false))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Add Scraped Feed", content, d.cfg.Theme)
+}
+
+func (d AddScrapedFeed) msgView() string {
+	style := lipgloss.NewStyle().Width(dialogWidth)
+	if d.err != nil {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.err.Error())
+	}
+	if d.addFeedMsg.IsInProgress() {
+		return style.Foreground(d.cfg.Theme.DialogMsg).Render("Adding...")
+	}
+	if d.addFeedMsg.IsFailed() {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.addFeedMsg.Err.Error())
+	}
+	return ""
+}
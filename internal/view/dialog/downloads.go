@@ -0,0 +1,154 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/download"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+// maxDownloadRows is how many downloads are shown at once.
+const maxDownloadRows = 6
+
+var (
+	toogleDownloadKey = key.NewBinding(key.WithKeys("p", " "), key.WithHelp("p/space", "pause/resume"))
+	removeDownloadKey = key.NewBinding(key.WithKeys("x", "delete"), key.WithHelp("x/delete", "remove"))
+)
+
+type Downloads struct {
+	cfg       *config.App
+	manager   *download.Manager
+	downloads []download.Download
+	cursor    int
+}
+
+func NewDownloads(cfg *config.App, manager *download.Manager) tea.Model {
+	return Downloads{
+		cfg:       cfg,
+		manager:   manager,
+		downloads: manager.Downloads(),
+	}
+}
+
+func (d Downloads) Init() tea.Cmd {
+	return nil
+}
+
+func (d Downloads) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case message.Downloads:
+		d.downloads = msg.Downloads
+		d.cursor = min(d.cursor, max(len(d.downloads)-1, 0))
+	case tea.KeyMsg:
+		return d.onKeyMsg(msg)
+	}
+	return d, nil
+}
+
+func (d Downloads) onKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, d.cfg.KeyMap.Up) {
+		d.cursor = max(d.cursor-1, 0)
+		return d, nil
+	}
+	if key.Matches(msg, d.cfg.KeyMap.Down) {
+		d.cursor = min(d.cursor+1, max(len(d.downloads)-1, 0))
+		return d, nil
+	}
+	if len(d.downloads) == 0 {
+		return d, nil
+	}
+
+	id := d.downloads[d.cursor].Enclosure.ID
+	if key.Matches(msg, toogleDownloadKey) {
+		return d, func() tea.Msg {
+			d.manager.Toogle(id)
+			return nil
+		}
+	}
+	if key.Matches(msg, removeDownloadKey) {
+		return d, func() tea.Msg {
+			d.manager.Remove(id)
+			return nil
+		}
+	}
+	return d, nil
+}
+
+func (d Downloads) View() string {
+	width := dialogWidth - 4 //nolint:mnd // horizontal padding
+	style := lipgloss.NewStyle().Width(width)
+
+	views := make([]string, 0, maxDownloadRows+1)
+	if len(d.downloads) == 0 {
+		views = append(views, style.Foreground(d.cfg.Theme.DialogMsg).Render("No downloads"))
+	}
+
+	// Keep the cursor in the rows shown.
+	start := max(d.cursor-maxDownloadRows+1, 0)
+	end := min(start+maxDownloadRows, len(d.downloads))
+	for i := start; i < end; i++ {
+		views = append(views, d.rowView(d.downloads[i], width, i == d.cursor))
+	}
+
+	help := fmt.Sprintf("%s %s · %s %s",
+		toogleDownloadKey.Help().Key, toogleDownloadKey.Help().Desc,
+		removeDownloadKey.Help().Key, removeDownloadKey.Help().Desc)
+	views = append(views, style.Foreground(d.cfg.Theme.HelpKeyDesc).Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Downloads", content, d.cfg.Theme)
+}
+
+func (d Downloads) rowView(v download.Download, width int, isSelected bool) string {
+	prompt := " "
+	titleStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitle)
+	descStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemDesc)
+	if isSelected {
+		prompt = lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitleActive).Render(">")
+		titleStyle = titleStyle.Foreground(d.cfg.Theme.ItemTitleActive)
+		descStyle = descStyle.Foreground(d.cfg.Theme.ItemDescActive)
+	}
+
+	title := v.Title
+	if title == "" {
+		title = v.Enclosure.Name()
+	}
+	title = ansi.Truncate(title, width-2, "...") //nolint:mnd // prompt + space
+	title = fmt.Sprintf("%s %s", prompt, titleStyle.Render(title))
+
+	state := string(v.State())
+	if v.Err != nil {
+		state = v.Err.Error()
+	}
+	size := rss.FormatSize(v.Size)
+	if total := v.Total; total > 0 {
+		size = fmt.Sprintf("%s / %s", size, rss.FormatSize(total))
+	}
+	info := fmt.Sprintf("%s %s", size, state)
+	bar := progressBar(v, width-ansi.StringWidth(info)-3) //nolint:mnd // prompt + spaces
+	desc := ansi.Truncate(fmt.Sprintf("  %s %s", bar, info), width, "...")
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, descStyle.Render(desc))
+}
+
+func progressBar(v download.Download, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	var done int
+	switch {
+	case v.State() == rss.DownloadDone:
+		done = width
+	case v.Total > 0:
+		done = int(int64(width) * min(v.Size, v.Total) / v.Total)
+	}
+	return strings.Repeat("█", done) + strings.Repeat("░", width-done)
+}
@@ -0,0 +1,99 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+type FeedFilter struct {
+	cfg                 *config.App
+	repo                rss.Repo
+	ti                  textinput.Model
+	updateFeedFilterMsg message.UpdateFeedFilter
+}
+
+func NewFeedFilter(cfg *config.App, repo rss.Repo) tea.Model {
+	return FeedFilter{
+		cfg:  cfg,
+		repo: repo,
+		ti:   newTextInput(cfg.Theme, "Command, e.g. xsltproc fix.xsl -"),
+	}
+}
+
+func (d FeedFilter) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (d FeedFilter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case message.UpdateFeedFilter:
+		return d.onUpdateFeedFilterMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, d.cfg.KeyMap.Enter) {
+			return d.onEnterKeyMsg()
+		}
+	}
+
+	d.ti, cmd = d.ti.Update(msg)
+	return d, cmd
+}
+
+func (d FeedFilter) onUpdateFeedFilterMsg(msg message.UpdateFeedFilter) (tea.Model, tea.Cmd) {
+	d.updateFeedFilterMsg = msg
+
+	var cmd tea.Cmd
+
+	switch {
+	case msg.IsInitial():
+		d.ti.SetValue(msg.Feed.Filter)
+		cmd = d.ti.Focus()
+	case msg.IsInProgress():
+		d.ti.Blur()
+	case msg.IsFailed():
+		cmd = d.ti.Focus()
+	}
+
+	return d, cmd
+}
+
+func (d FeedFilter) onEnterKeyMsg() (tea.Model, tea.Cmd) {
+	if d.updateFeedFilterMsg.IsInProgress() {
+		return d, nil
+	}
+
+	// An empty command removes the filter.
+	v := strings.TrimSpace(d.ti.Value())
+	return d, message.UpdateFeedFilterCmd(d.updateFeedFilterMsg.Feed, v, d.repo)
+}
+
+func (d FeedFilter) View() string {
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		inputView(d.ti, d.cfg.Theme),
+		fmt.Sprintf("%s\n", d.msgView()),
+		actionsView(d.cfg.Theme, false),
+	)
+
+	return render("Feed Filter", content, d.cfg.Theme)
+}
+
+func (d FeedFilter) msgView() string {
+	style := lipgloss.NewStyle().Width(dialogWidth)
+	if d.updateFeedFilterMsg.IsInProgress() {
+		return style.Foreground(d.cfg.Theme.DialogMsg).Render("Saving...")
+	}
+	if d.updateFeedFilterMsg.IsFailed() {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.updateFeedFilterMsg.Err.Error())
+	}
+	return ""
+}
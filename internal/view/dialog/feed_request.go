@@ -0,0 +1,159 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+const (
+	inputHeaders = iota
+	inputCookie
+	inputUsername
+	inputPassword
+)
+
+// Inputs are switched with tab and arrows, the up/down hotkeys
+// have letters which are typed into inputs.
+var (
+	nextInputKey = key.NewBinding(key.WithKeys("tab", "down"))
+	prevInputKey = key.NewBinding(key.WithKeys("shift+tab", "up"))
+)
+
+type FeedRequest struct {
+	cfg                  *config.App
+	repo                 rss.Repo
+	inputs               []textinput.Model
+	focus                int
+	err                  error
+	updateFeedRequestMsg message.UpdateFeedRequest
+}
+
+func NewFeedRequest(cfg *config.App, repo rss.Repo) tea.Model {
+	inputs := []textinput.Model{
+		newTextInput(cfg.Theme, "Headers, e.g. Authorization: Bearer xxx; X-Key: yyy"),
+		newTextInput(cfg.Theme, "Cookie, e.g. a=1; b=2"),
+		newTextInput(cfg.Theme, "Basic auth username"),
+		newTextInput(cfg.Theme, "Basic auth password"),
+	}
+	inputs[inputPassword].EchoMode = textinput.EchoPassword
+	for i := range inputs {
+		if i != inputHeaders {
+			inputs[i].Blur()
+		}
+	}
+
+	return FeedRequest{
+		cfg:    cfg,
+		repo:   repo,
+		inputs: inputs,
+	}
+}
+
+func (d FeedRequest) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (d FeedRequest) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case message.UpdateFeedRequest:
+		return d.onUpdateFeedRequestMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, d.cfg.KeyMap.Enter) {
+			return d.onEnterKeyMsg()
+		}
+		if key.Matches(msg, nextInputKey) {
+			return d, d.focusInput((d.focus + 1) % len(d.inputs))
+		}
+		if key.Matches(msg, prevInputKey) {
+			return d, d.focusInput((d.focus + len(d.inputs) - 1) % len(d.inputs))
+		}
+	}
+
+	d.inputs[d.focus], cmd = d.inputs[d.focus].Update(msg)
+	return d, cmd
+}
+
+func (d *FeedRequest) focusInput(i int) tea.Cmd {
+	d.inputs[d.focus].Blur()
+	d.focus = i
+	return d.inputs[d.focus].Focus()
+}
+
+func (d FeedRequest) onUpdateFeedRequestMsg(msg message.UpdateFeedRequest) (tea.Model, tea.Cmd) {
+	d.updateFeedRequestMsg = msg
+	d.err = nil
+
+	var cmd tea.Cmd
+
+	switch {
+	case msg.IsInitial():
+		r := msg.Feed.Request
+		d.inputs[inputHeaders].SetValue(rss.FormatHeaders(r.Headers))
+		d.inputs[inputCookie].SetValue(r.Cookie)
+		d.inputs[inputUsername].SetValue(r.Username)
+		d.inputs[inputPassword].SetValue(r.Password)
+		cmd = d.focusInput(inputHeaders)
+	case msg.IsInProgress():
+		d.inputs[d.focus].Blur()
+	case msg.IsFailed():
+		cmd = d.inputs[d.focus].Focus()
+	}
+
+	return d, cmd
+}
+
+func (d FeedRequest) onEnterKeyMsg() (tea.Model, tea.Cmd) {
+	if d.updateFeedRequestMsg.IsInProgress() {
+		return d, nil
+	}
+
+	headers, err := rss.ParseHeaders(d.inputs[inputHeaders].Value())
+	if err != nil {
+		d.err = err
+		return d, nil
+	}
+
+	r := rss.Request{
+		Headers:  headers,
+		Cookie:   strings.TrimSpace(d.inputs[inputCookie].Value()),
+		Username: strings.TrimSpace(d.inputs[inputUsername].Value()),
+		Password: d.inputs[inputPassword].Value(),
+	}
+	return d, message.UpdateFeedRequestCmd(d.updateFeedRequestMsg.Feed, r, d.repo)
+}
+
+func (d FeedRequest) View() string {
+	views := make([]string, 0, len(d.inputs)+2) //nolint:mnd // msg + actions
+	for _, i := range d.inputs {
+		views = append(views, inputView(i, d.cfg.Theme))
+	}
+	views = append(views, fmt.Sprintf("%s\n", d.msgView()))
+	views = append(views, actionsView(d.cfg.Theme, false))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Feed Request", content, d.cfg.Theme)
+}
+
+func (d FeedRequest) msgView() string {
+	style := lipgloss.NewStyle().Width(dialogWidth)
+	if d.err != nil {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.err.Error())
+	}
+	if d.updateFeedRequestMsg.IsInProgress() {
+		return style.Foreground(d.cfg.Theme.DialogMsg).Render("Saving...")
+	}
+	if d.updateFeedRequestMsg.IsFailed() {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.updateFeedRequestMsg.Err.Error())
+	}
+	return ""
+}
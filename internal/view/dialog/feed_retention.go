@@ -0,0 +1,174 @@
+package dialog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+)
+
+const (
+	inputKeepItems = iota
+	inputKeepReadDays
+)
+
+type FeedRetention struct {
+	cfg                    *config.App
+	repo                   rss.Repo
+	inputs                 []textinput.Model
+	focus                  int
+	err                    error
+	updateFeedRetentionMsg message.UpdateFeedRetention
+}
+
+func NewFeedRetention(cfg *config.App, repo rss.Repo) tea.Model {
+	inputs := []textinput.Model{
+		newTextInput(cfg.Theme, "Keep latest N items, empty for global setting, 0 keeps all"),
+		newTextInput(cfg.Theme, "Drop read items older than N days, empty for global setting, 0 keeps all"),
+	}
+	inputs[inputKeepReadDays].Blur()
+
+	return FeedRetention{
+		cfg:    cfg,
+		repo:   repo,
+		inputs: inputs,
+	}
+}
+
+func (d FeedRetention) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (d FeedRetention) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case message.UpdateFeedRetention:
+		return d.onUpdateFeedRetentionMsg(msg)
+	case tea.KeyMsg:
+		if key.Matches(msg, d.cfg.KeyMap.Enter) {
+			return d.onEnterKeyMsg()
+		}
+		if key.Matches(msg, nextInputKey) {
+			return d, d.focusInput((d.focus + 1) % len(d.inputs))
+		}
+		if key.Matches(msg, prevInputKey) {
+			return d, d.focusInput((d.focus + len(d.inputs) - 1) % len(d.inputs))
+		}
+	}
+
+	d.inputs[d.focus], cmd = d.inputs[d.focus].Update(msg)
+	return d, cmd
+}
+
+func (d *FeedRetention) focusInput(i int) tea.Cmd {
+	d.inputs[d.focus].Blur()
+	d.focus = i
+	return d.inputs[d.focus].Focus()
+}
+
+func (d FeedRetention) onUpdateFeedRetentionMsg(msg message.UpdateFeedRetention) (tea.Model, tea.Cmd) {
+	d.updateFeedRetentionMsg = msg
+	d.err = nil
+
+	var cmd tea.Cmd
+
+	switch {
+	case msg.IsInitial():
+		r := msg.Feed.Retention
+		d.inputs[inputKeepItems].SetValue(formatRetention(r.KeepItems))
+		d.inputs[inputKeepReadDays].SetValue(formatRetention(r.KeepReadDays))
+		cmd = d.focusInput(inputKeepItems)
+	case msg.IsInProgress():
+		d.inputs[d.focus].Blur()
+	case msg.IsFailed():
+		cmd = d.inputs[d.focus].Focus()
+	}
+
+	return d, cmd
+}
+
+func (d FeedRetention) onEnterKeyMsg() (tea.Model, tea.Cmd) {
+	if d.updateFeedRetentionMsg.IsInProgress() {
+		return d, nil
+	}
+
+	keepItems, err := parseRetention(d.inputs[inputKeepItems].Value())
+	if err != nil {
+		d.err = fmt.Errorf("invalid items: %w", err)
+		return d, nil
+	}
+	keepReadDays, err := parseRetention(d.inputs[inputKeepReadDays].Value())
+	if err != nil {
+		d.err = fmt.Errorf("invalid days: %w", err)
+		return d, nil
+	}
+
+	r := rss.Retention{
+		KeepItems:    keepItems,
+		KeepReadDays: keepReadDays,
+	}
+	return d, message.UpdateFeedRetentionCmd(d.updateFeedRetentionMsg.Feed, r, d.repo)
+}
+
+// formatRetention shows a setting of a feed, the global setting is
+// empty and keeping all is 0, see rss.Retention.Of.
+func formatRetention(v int) string {
+	switch {
+	case v == 0:
+		return ""
+	case v < 0:
+		return "0"
+	default:
+		return strconv.Itoa(v)
+	}
+}
+
+// parseRetention is the reverse of formatRetention.
+func parseRetention(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%q is not a number of 0 or more", s)
+	}
+	if v == 0 {
+		return -1, nil
+	}
+	return v, nil
+}
+
+func (d FeedRetention) View() string {
+	views := make([]string, 0, len(d.inputs)+2) //nolint:mnd // msg + actions
+	for _, i := range d.inputs {
+		views = append(views, inputView(i, d.cfg.Theme))
+	}
+	views = append(views, fmt.Sprintf("%s\n", d.msgView()))
+	views = append(views, actionsView(d.cfg.Theme, false))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Feed Retention", content, d.cfg.Theme)
+}
+
+func (d FeedRetention) msgView() string {
+	style := lipgloss.NewStyle().Width(dialogWidth)
+	if d.err != nil {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.err.Error())
+	}
+	if d.updateFeedRetentionMsg.IsInProgress() {
+		return style.Foreground(d.cfg.Theme.DialogMsg).Render("Saving...")
+	}
+	if d.updateFeedRetentionMsg.IsFailed() {
+		return style.Foreground(d.cfg.Theme.Error).Render(d.updateFeedRetentionMsg.Err.Error())
+	}
+	return ""
+}
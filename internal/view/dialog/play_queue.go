@@ -0,0 +1,133 @@
+package dialog
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/player"
+)
+
+// maxTrackRows is how many tracks are shown at once.
+const maxTrackRows = 6
+
+var (
+	skipTrackKey = key.NewBinding(key.WithKeys("x", "delete"), key.WithHelp("x/delete", "skip"))
+	stopPlayKey  = key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "stop all"))
+)
+
+// PlayQueue lists the playing track followed by the queued ones.
+type PlayQueue struct {
+	cfg    *config.App
+	player *player.Player
+	tracks []player.Track
+	cursor int
+}
+
+func NewPlayQueue(cfg *config.App, p *player.Player) tea.Model {
+	return PlayQueue{
+		cfg:    cfg,
+		player: p,
+		tracks: p.Queue(),
+	}
+}
+
+func (d PlayQueue) Init() tea.Cmd {
+	return nil
+}
+
+func (d PlayQueue) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case message.Played:
+		d.refresh()
+	case tea.KeyMsg:
+		return d.onKeyMsg(msg)
+	}
+	return d, nil
+}
+
+func (d *PlayQueue) refresh() {
+	d.tracks = d.player.Queue()
+	d.cursor = min(d.cursor, max(len(d.tracks)-1, 0))
+}
+
+func (d PlayQueue) onKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, d.cfg.KeyMap.Up) {
+		d.cursor = max(d.cursor-1, 0)
+		return d, nil
+	}
+	if key.Matches(msg, d.cfg.KeyMap.Down) {
+		d.cursor = min(d.cursor+1, max(len(d.tracks)-1, 0))
+		return d, nil
+	}
+	if len(d.tracks) == 0 {
+		return d, nil
+	}
+
+	// The playing track leaves the queue once the player stopped, a
+	// Played message refreshes the queue then.
+	if key.Matches(msg, skipTrackKey) {
+		d.player.Skip(d.tracks[d.cursor].FeedItem.ID)
+		d.refresh()
+		return d, nil
+	}
+	if key.Matches(msg, stopPlayKey) {
+		d.player.Stop()
+		d.refresh()
+		return d, nil
+	}
+	return d, nil
+}
+
+func (d PlayQueue) View() string {
+	width := dialogWidth - 4 //nolint:mnd // horizontal padding
+	style := lipgloss.NewStyle().Width(width)
+
+	views := make([]string, 0, maxTrackRows+1)
+	if len(d.tracks) == 0 {
+		views = append(views, style.Foreground(d.cfg.Theme.DialogMsg).Render("Nothing playing"))
+	}
+
+	// Keep the cursor in the rows shown.
+	start := max(d.cursor-maxTrackRows+1, 0)
+	end := min(start+maxTrackRows, len(d.tracks))
+	for i := start; i < end; i++ {
+		views = append(views, d.rowView(d.tracks[i], i, width))
+	}
+
+	help := fmt.Sprintf("%s %s · %s %s",
+		skipTrackKey.Help().Key, skipTrackKey.Help().Desc,
+		stopPlayKey.Help().Key, stopPlayKey.Help().Desc)
+	views = append(views, style.Foreground(d.cfg.Theme.HelpKeyDesc).Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Play Queue", content, d.cfg.Theme)
+}
+
+func (d PlayQueue) rowView(t player.Track, i, width int) string {
+	prompt := " "
+	titleStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitle)
+	descStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemDesc)
+	if i == d.cursor {
+		prompt = lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitleActive).Render(">")
+		titleStyle = titleStyle.Foreground(d.cfg.Theme.ItemTitleActive)
+		descStyle = descStyle.Foreground(d.cfg.Theme.ItemDescActive)
+	}
+
+	title := ansi.Truncate(t.FeedItem.Title, width-2, "...") //nolint:mnd // prompt + space
+	title = fmt.Sprintf("%s %s", prompt, titleStyle.Render(title))
+
+	// Tracks are only queued while one plays, the first one plays.
+	state := "playing"
+	if i > 0 {
+		state = fmt.Sprintf("queued #%d", i)
+	}
+	desc := fmt.Sprintf("%s · %s", state, t.FeedItem.FeedName)
+	desc = "  " + descStyle.Render(ansi.Truncate(desc, width-2, "...")) //nolint:mnd // indent
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, desc)
+}
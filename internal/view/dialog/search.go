@@ -0,0 +1,216 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/message"
+	"github.com/lakerszhy/rssx/internal/rss"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+const (
+	// maxSearchRows is how many results are shown at once.
+	maxSearchRows = 4
+	// searchLimit is how many results a search gets.
+	searchLimit = 50
+	// searchDelay waits for typing to pause before searching.
+	searchDelay = 200 * time.Millisecond
+)
+
+// Results are picked with arrows, the up/down hotkeys have letters
+// which are typed into the input.
+var (
+	nextResultKey = key.NewBinding(key.WithKeys("down"), key.WithHelp("↑/↓", "select"))
+	prevResultKey = key.NewBinding(key.WithKeys("up"))
+)
+
+// searchTick searches for query, unless more was typed meanwhile.
+type searchTick struct {
+	query string
+}
+
+type Search struct {
+	cfg            *config.App
+	repo           rss.Repo
+	htmlPolicy     *bluemonday.Policy
+	ti             textinput.Model
+	cursor         int
+	searchItemsMsg message.SearchItems
+}
+
+func NewSearch(cfg *config.App, repo rss.Repo) tea.Model {
+	return Search{
+		cfg:        cfg,
+		repo:       repo,
+		htmlPolicy: bluemonday.StrictPolicy(),
+		ti:         newTextInput(cfg.Theme, "Search titles, content, authors and feeds"),
+	}
+}
+
+func (d Search) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (d Search) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case message.SearchItems:
+		return d.onSearchItemsMsg(msg)
+	case searchTick:
+		if msg.query != d.query() {
+			return d, nil
+		}
+		return d, message.SearchItemsCmd(msg.query, searchLimit, d.repo)
+	case tea.KeyMsg:
+		if key.Matches(msg, d.cfg.KeyMap.Enter) {
+			return d, d.onEnterKeyMsg()
+		}
+		if key.Matches(msg, nextResultKey) {
+			d.cursor = min(d.cursor+1, max(len(d.searchItemsMsg.Results)-1, 0))
+			return d, nil
+		}
+		if key.Matches(msg, prevResultKey) {
+			d.cursor = max(d.cursor-1, 0)
+			return d, nil
+		}
+	}
+
+	query := d.query()
+	d.ti, cmd = d.ti.Update(msg)
+	if d.query() == query {
+		return d, cmd
+	}
+
+	query = d.query()
+	if query == "" {
+		d.searchItemsMsg = message.SearchItems{}
+		d.cursor = 0
+		return d, cmd
+	}
+	tick := tea.Tick(searchDelay, func(time.Time) tea.Msg {
+		return searchTick{query: query}
+	})
+	return d, tea.Batch(cmd, tick)
+}
+
+func (d Search) query() string {
+	return strings.TrimSpace(d.ti.Value())
+}
+
+func (d Search) onSearchItemsMsg(msg message.SearchItems) (tea.Model, tea.Cmd) {
+	// Results of an older query are stale.
+	if msg.Query != d.query() {
+		return d, nil
+	}
+
+	// Keep the last results until the new ones are in.
+	if msg.IsInProgress() {
+		msg.Results = d.searchItemsMsg.Results
+	}
+	d.searchItemsMsg = msg
+	if msg.IsSuccessful() {
+		d.cursor = 0
+	}
+	return d, nil
+}
+
+func (d Search) onEnterKeyMsg() tea.Cmd {
+	results := d.searchItemsMsg.Results
+	if len(results) == 0 {
+		return nil
+	}
+
+	i := results[d.cursor].Item
+	return func() tea.Msg {
+		return message.NewOpenItem(i)
+	}
+}
+
+func (d Search) View() string {
+	width := dialogWidth - 4 //nolint:mnd // horizontal padding
+
+	views := make([]string, 0, maxSearchRows+3) //nolint:mnd // input + msg + help
+	views = append(views, inputView(d.ti, d.cfg.Theme))
+
+	// Keep the cursor in the rows shown.
+	results := d.searchItemsMsg.Results
+	start := max(d.cursor-maxSearchRows+1, 0)
+	end := min(start+maxSearchRows, len(results))
+	for i := start; i < end; i++ {
+		views = append(views, d.rowView(results[i], width, i == d.cursor))
+	}
+
+	views = append(views, d.msgView(width))
+
+	help := fmt.Sprintf("%s %s · %s open",
+		nextResultKey.Help().Key, nextResultKey.Help().Desc, d.cfg.KeyMap.Enter.Help().Key)
+	style := lipgloss.NewStyle().Width(width).Foreground(d.cfg.Theme.HelpKeyDesc)
+	views = append(views, style.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, views...)
+	return render("Search", content, d.cfg.Theme)
+}
+
+func (d Search) rowView(r rss.SearchResult, width int, isSelected bool) string {
+	prompt := " "
+	titleStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitle)
+	descStyle := lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemDesc)
+	if isSelected {
+		prompt = lipgloss.NewStyle().Foreground(d.cfg.Theme.ItemTitleActive).Render(">")
+		titleStyle = titleStyle.Foreground(d.cfg.Theme.ItemTitleActive)
+		descStyle = descStyle.Foreground(d.cfg.Theme.ItemDescActive)
+	}
+
+	title := ansi.Truncate(r.Item.Title, width-2, "...") //nolint:mnd // prompt + space
+	title = fmt.Sprintf("%s %s", prompt, titleStyle.Render(title))
+
+	info := fmt.Sprintf("%s · %s", r.Item.FeedName, r.Item.Date().Format(time.DateOnly))
+	info = "  " + descStyle.Render(ansi.Truncate(info, width-2, "...")) //nolint:mnd // indent
+
+	snippet := d.snippetView(r.PlainSnippet(d.htmlPolicy), descStyle)
+	snippet = "  " + ansi.Truncate(snippet, width-2, "...") //nolint:mnd // indent
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, info, snippet)
+}
+
+// snippetView highlights the matched words of v.
+func (d Search) snippetView(v string, style lipgloss.Style) string {
+	matchStyle := style.Foreground(d.cfg.Theme.Starred).Bold(true)
+
+	var b strings.Builder
+	for i, part := range strings.Split(v, rss.MatchStart) {
+		match, rest, ok := strings.Cut(part, rss.MatchEnd)
+		if i == 0 || !ok {
+			b.WriteString(style.Render(strings.ReplaceAll(part, rss.MatchEnd, "")))
+			continue
+		}
+		b.WriteString(matchStyle.Render(match))
+		b.WriteString(style.Render(rest))
+	}
+	return b.String()
+}
+
+func (d Search) msgView(width int) string {
+	style := lipgloss.NewStyle().Width(width).Foreground(d.cfg.Theme.DialogMsg)
+	msg := d.searchItemsMsg
+	switch {
+	case msg.IsFailed():
+		return style.Foreground(d.cfg.Theme.Error).Render(msg.Err.Error())
+	case msg.IsInProgress():
+		return style.Render("Searching...")
+	case msg.IsSuccessful() && len(msg.Results) == 0:
+		return style.Render("No results")
+	case msg.IsSuccessful():
+		return style.Render(fmt.Sprintf("%d of %d results", d.cursor+1, len(msg.Results)))
+	}
+	return ""
+}
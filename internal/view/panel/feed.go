@@ -6,6 +6,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,12 +18,16 @@ import (
 	"github.com/pkg/browser"
 )
 
+// smartFeedsLength is how many smart feeds lead the list.
+const smartFeedsLength = 4
+
 type Feed struct {
 	cfg       *config.App
 	logger    *slog.Logger
 	repo      rss.Repo
 	listView  listView[rss.Feed]
 	isFocused bool
+	counts    rss.UnreadCounts
 }
 
 func NewFeed(cfg *config.App, logger *slog.Logger, repo rss.Repo) Feed {
@@ -45,16 +50,22 @@ func (p Feed) Update(msg tea.Msg) (Feed, tea.Cmd) {
 		return p, p.setFeeds(msg.Feeds)
 	case message.AddFeed:
 		return p, p.addFeed(msg.Feed)
-	case message.ToogleRead:
-		return p, p.onToogleRead(msg)
-	case message.MarkAllRead:
-		return p, p.onMarkAllRead(msg)
-	case message.ToogleStarred:
-		return p, p.onToogleStarred(msg)
+	case message.LoadCounts:
+		return p, p.onLoadCounts(msg)
+	case message.ToogleUpdatedUnread:
+		return p, p.onToogleUpdatedUnread(msg)
 	case message.DeleteFeed:
 		return p, p.onDeleteFeed(msg)
 	case message.RenameFeed:
 		return p, p.onRenameFeed(msg)
+	case message.UpdateFeedRequest:
+		return p, p.onUpdateFeedRequest(msg)
+	case message.UpdateFeedFilter:
+		return p, p.onUpdateFeedFilter(msg)
+	case message.UpdateFeedRetention:
+		return p, p.onUpdateFeedRetention(msg)
+	case message.ToogleFullContent:
+		return p, p.onToogleFullContent(msg)
 	case tea.KeyMsg:
 		if key.Matches(msg, p.cfg.KeyMap.DeleteFeed) {
 			return p, p.onDeleteFeedKeyMsg()
@@ -62,6 +73,15 @@ func (p Feed) Update(msg tea.Msg) (Feed, tea.Cmd) {
 		if key.Matches(msg, p.cfg.KeyMap.RenameFeed) {
 			return p, p.onRenameFeedKeyMsg()
 		}
+		if key.Matches(msg, p.cfg.KeyMap.FeedRequest) {
+			return p, p.onFeedRequestKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.FeedFilter) {
+			return p, p.onFeedFilterKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.FeedRetention) {
+			return p, p.onFeedRetentionKeyMsg()
+		}
 		if key.Matches(msg, p.cfg.KeyMap.Open) {
 			p.onOpenKeyMsg()
 			return p, nil
@@ -69,6 +89,15 @@ func (p Feed) Update(msg tea.Msg) (Feed, tea.Cmd) {
 		if key.Matches(msg, p.cfg.KeyMap.MarkAllRead) {
 			return p, p.onMarkAllReadKeyMsg()
 		}
+		if key.Matches(msg, p.cfg.KeyMap.UpdatedUnread) {
+			return p, p.onUpdatedUnreadKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.FullContent) {
+			return p, p.onFullContentKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.Backfill) {
+			return p, p.onBackfillKeyMsg()
+		}
 	}
 
 	var cmd tea.Cmd
@@ -82,15 +111,17 @@ func (p Feed) Update(msg tea.Msg) (Feed, tea.Cmd) {
 	}
 	cmds = append(cmds, cmd)
 
-	// if select smart feed, update smart feeds
-	if i := p.listView.selectedItem(); i != nil && i.IsSmart() {
-		p.updateSmartFeeds(p.listView.items())
-	}
-
 	return p, tea.Batch(cmds...)
 }
 
-func (p *Feed) UpdateFeed(f rss.Feed) tea.Cmd {
+// UpdateFeed replaces f after a refresh or backfill. Items it got are
+// stored already, the selected feed loads them when it may list them.
+// changed reloads it too, e.g. when items were pruned.
+func (p *Feed) UpdateFeed(f rss.Feed, changed bool) tea.Cmd {
+	changed = changed || len(f.Items) > 0
+	f.Unload()
+	p.counts.Apply(&f)
+
 	feeds := p.listView.items()
 	idx := slices.IndexFunc(feeds, func(i rss.Feed) bool {
 		return f.ID == i.ID
@@ -101,8 +132,37 @@ func (p *Feed) UpdateFeed(f rss.Feed) tea.Cmd {
 
 	p.listView.setItems(feeds)
 	p.updateSmartFeeds(feeds)
+
+	selected := p.listView.selectedItem()
+	if !changed {
+		return func() tea.Msg {
+			return message.NewSelectFeed(selected)
+		}
+	}
+
+	reload := selected != nil && (selected.Is(f) || selected.IsSmart())
+	cmd := func() tea.Msg {
+		if reload {
+			return message.NewReloadFeed(selected)
+		}
+		return message.NewSelectFeed(selected)
+	}
+	return tea.Batch(cmd, message.LoadCountsCmd(p.repo))
+}
+
+// OpenItem selects the feed of i, the item panel then selects i.
+func (p *Feed) OpenItem(i rss.FeedItem) tea.Cmd {
+	idx := slices.IndexFunc(p.listView.items(), func(f rss.Feed) bool {
+		return !f.IsSmart() && f.ID == i.FeedID
+	})
+	if idx < 0 {
+		return nil
+	}
+	p.listView.selectByIndex(idx)
+
+	selected := p.listView.selectedItem()
 	return func() tea.Msg {
-		return message.NewSelectFeed(p.listView.selectedItem())
+		return message.NewSelectFeedOpenItem(selected, i)
 	}
 }
 
@@ -111,24 +171,72 @@ func (p *Feed) AddFeeds(feeds []rss.Feed) tea.Cmd {
 	return p.setFeeds(feeds)
 }
 
-func (p *Feed) setFeeds(feeds []rss.Feed) tea.Cmd {
-	sort.SliceStable(feeds, func(i, j int) bool {
-		return strings.ToLower(feeds[i].Name) < strings.ToLower(feeds[j].Name)
-	})
+// ReloadFeeds replaces the feeds of ids after another process changed
+// them, feeds is all feeds. The selected feed stays selected and loads
+// its items again only if it is one of them.
+func (p *Feed) ReloadFeeds(ids []int64, feeds []rss.Feed) tea.Cmd {
+	current := make(map[int64]rss.Feed)
+	for _, f := range p.NormalFeeds() {
+		current[f.ID] = f
+	}
+	for i, f := range feeds {
+		if c, ok := current[f.ID]; ok && !slices.Contains(ids, f.ID) {
+			feeds[i] = c
+			continue
+		}
+		feeds[i].Unload()
+		p.counts.Apply(&feeds[i])
+	}
 
+	selected := p.listView.selectedItem()
+	sortFeeds(feeds)
 	p.updateSmartFeeds(feeds)
 
-	var cmd tea.Cmd
+	items := p.listView.items()
+	idx := -1
+	if selected != nil {
+		idx = slices.IndexFunc(items, selected.Is)
+	}
+	// A feed selected in its place has no items loaded yet.
+	reload := idx < 0 || selected.IsSmart() || slices.Contains(ids, selected.ID)
+	if idx < 0 {
+		idx = min(smartFeedsLength, len(items)-1)
+	}
+	p.listView.selectByIndex(idx)
+
+	selected = p.listView.selectedItem()
+	cmd := func() tea.Msg {
+		if reload {
+			return message.NewReloadFeed(selected)
+		}
+		return message.NewSelectFeed(selected)
+	}
+	return tea.Batch(cmd, message.LoadCountsCmd(p.repo))
+}
+
+func (p *Feed) setFeeds(feeds []rss.Feed) tea.Cmd {
+	sortFeeds(feeds)
+	p.updateSmartFeeds(feeds)
+
+	cmds := []tea.Cmd{message.LoadCountsCmd(p.repo)}
 	if len(feeds) > 0 {
-		p.listView.selectByIndex(3) //nolint:mnd // smart feeds length
-		cmd = func() tea.Msg {
+		p.listView.selectByIndex(smartFeedsLength)
+		cmd := func() tea.Msg {
 			return message.NewSelectFeed(&feeds[0])
 		}
+		cmds = append(cmds, cmd)
 	}
-	return cmd
+	return tea.Batch(cmds...)
+}
+
+func sortFeeds(feeds []rss.Feed) {
+	sort.SliceStable(feeds, func(i, j int) bool {
+		return strings.ToLower(feeds[i].Name) < strings.ToLower(feeds[j].Name)
+	})
 }
 
 func (p *Feed) addFeed(f rss.Feed) tea.Cmd {
+	f.Unload()
 	feeds := p.listView.items()
 	feeds = append(feeds, f)
 	p.setFeeds(feeds)
@@ -146,47 +254,35 @@ func (p *Feed) addFeed(f rss.Feed) tea.Cmd {
 	cmd := func() tea.Msg {
 		return message.NewSelectFeed(&f)
 	}
-	return cmd
+	return tea.Batch(cmd, message.LoadCountsCmd(p.repo))
 }
 
-func (p *Feed) onToogleRead(msg message.ToogleRead) tea.Cmd {
-	cmd := p.update(func(f *rss.Feed) {
-		f.ToogleRead(msg.ItemID)
-	})
-
-	// if select smart feed, don't update.
-	// Otherwise, item in item panel will be gone.
-	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
-		p.updateSmartFeeds(p.listView.items())
+func (p *Feed) onLoadCounts(msg message.LoadCounts) tea.Cmd {
+	if msg.IsFailed() {
+		p.logger.Error("load unread counts failed", "err", msg.Err)
+		return nil
 	}
 
-	return cmd
+	p.counts = msg.Counts
+	return p.update(func(f *rss.Feed) {
+		p.counts.Apply(f)
+	})
 }
 
-func (p *Feed) onMarkAllRead(msg message.MarkAllRead) tea.Cmd {
-	cmd := p.update(func(f *rss.Feed) {
-		f.MarkAllRead(msg.ItemIDs)
+func (p *Feed) onToogleUpdatedUnread(msg message.ToogleUpdatedUnread) tea.Cmd {
+	return p.update(func(f *rss.Feed) {
+		if f.ID == msg.Feed.ID {
+			f.MarkUpdatedUnread = msg.Feed.MarkUpdatedUnread
+		}
 	})
-
-	// if select smart feed, don't update.
-	// Otherwise, item in item panel will be gone.
-	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
-		p.updateSmartFeeds(p.listView.items())
-	}
-
-	return cmd
 }
 
-func (p *Feed) onToogleStarred(msg message.ToogleStarred) tea.Cmd {
-	cmd := p.update(func(f *rss.Feed) {
-		f.ToogleStarred(msg.ItemID)
+func (p *Feed) onToogleFullContent(msg message.ToogleFullContent) tea.Cmd {
+	return p.update(func(f *rss.Feed) {
+		if f.ID == msg.Feed.ID {
+			f.ExtractFullContent = msg.Feed.ExtractFullContent
+		}
 	})
-
-	if i := p.listView.selectedItem(); i == nil && !i.IsSmart() {
-		p.updateSmartFeeds(p.listView.items())
-	}
-
-	return cmd
 }
 
 func (p *Feed) onDeleteFeed(msg message.DeleteFeed) tea.Cmd {
@@ -206,7 +302,7 @@ func (p *Feed) onDeleteFeed(msg message.DeleteFeed) tea.Cmd {
 		return message.NewSelectFeed(p.listView.selectedItem())
 	}
 
-	return cmd
+	return tea.Batch(cmd, message.LoadCountsCmd(p.repo))
 }
 
 func (p *Feed) onRenameFeed(msg message.RenameFeed) tea.Cmd {
@@ -217,6 +313,32 @@ func (p *Feed) onRenameFeed(msg message.RenameFeed) tea.Cmd {
 	})
 }
 
+func (p *Feed) onUpdateFeedRequest(msg message.UpdateFeedRequest) tea.Cmd {
+	return p.update(func(f *rss.Feed) {
+		if f.ID == msg.Feed.ID {
+			f.Request = msg.Feed.Request
+		}
+	})
+}
+
+func (p *Feed) onUpdateFeedFilter(msg message.UpdateFeedFilter) tea.Cmd {
+	return p.update(func(f *rss.Feed) {
+		if f.ID == msg.Feed.ID {
+			f.Filter = msg.Feed.Filter
+			f.ETag = msg.Feed.ETag
+			f.LastModified = msg.Feed.LastModified
+		}
+	})
+}
+
+func (p *Feed) onUpdateFeedRetention(msg message.UpdateFeedRetention) tea.Cmd {
+	return p.update(func(f *rss.Feed) {
+		if f.ID == msg.Feed.ID {
+			f.Retention = msg.Feed.Retention
+		}
+	})
+}
+
 func (p Feed) onDeleteFeedKeyMsg() tea.Cmd {
 	var cmd tea.Cmd
 	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
@@ -237,6 +359,62 @@ func (p Feed) onRenameFeedKeyMsg() tea.Cmd {
 	return cmd
 }
 
+func (p Feed) onFeedRequestKeyMsg() tea.Cmd {
+	var cmd tea.Cmd
+	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
+		cmd = func() tea.Msg {
+			return message.NewUpdateFeedRequestInitial(*i)
+		}
+	}
+	return cmd
+}
+
+func (p Feed) onFeedFilterKeyMsg() tea.Cmd {
+	var cmd tea.Cmd
+	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
+		cmd = func() tea.Msg {
+			return message.NewUpdateFeedFilterInitial(*i)
+		}
+	}
+	return cmd
+}
+
+func (p Feed) onFeedRetentionKeyMsg() tea.Cmd {
+	var cmd tea.Cmd
+	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
+		cmd = func() tea.Msg {
+			return message.NewUpdateFeedRetentionInitial(*i)
+		}
+	}
+	return cmd
+}
+
+func (p Feed) onUpdatedUnreadKeyMsg() tea.Cmd {
+	i := p.listView.selectedItem()
+	if i == nil || i.IsSmart() {
+		return nil
+	}
+	return message.ToogleUpdatedUnreadCmd(*i, p.repo)
+}
+
+func (p Feed) onFullContentKeyMsg() tea.Cmd {
+	i := p.listView.selectedItem()
+	if i == nil || i.IsSmart() {
+		return nil
+	}
+	return message.ToogleFullContentCmd(*i, p.repo)
+}
+
+func (p Feed) onBackfillKeyMsg() tea.Cmd {
+	var cmd tea.Cmd
+	if i := p.listView.selectedItem(); i != nil && !i.IsSmart() {
+		cmd = func() tea.Msg {
+			return message.NewBackfillInitial(*i)
+		}
+	}
+	return cmd
+}
+
 func (p Feed) onOpenKeyMsg() {
 	i := p.listView.selectedItem()
 	if i == nil || i.IsSmart() {
@@ -249,19 +427,13 @@ func (p Feed) onOpenKeyMsg() {
 }
 
 func (p Feed) onMarkAllReadKeyMsg() tea.Cmd {
+	// The unread count may lag behind, marking read items is harmless.
 	f := p.listView.selectedItem()
-	if f == nil || f.UnreadCount() == 0 {
+	if f == nil {
 		return nil
 	}
 
-	var itemIDs []int64
-	for _, i := range f.Items {
-		if !i.IsRead {
-			itemIDs = append(itemIDs, i.ID)
-		}
-	}
-
-	return message.MarkAllReadCmd(itemIDs, p.repo)
+	return message.MarkAllReadCmd(*f, p.repo)
 }
 
 func (p *Feed) update(fn func(f *rss.Feed)) tea.Cmd {
@@ -280,41 +452,21 @@ func (p *Feed) update(fn func(f *rss.Feed)) tea.Cmd {
 
 func (p *Feed) updateSmartFeeds(feeds []rss.Feed) {
 	normalFeeds := []rss.Feed{}
-
-	todayFeed := rss.NewTodayFeed()
-	unreadFeed := rss.NewUnreadFeed()
-	starredFeed := rss.NewStarredFeed()
-
 	for _, f := range feeds {
-		if f.IsSmart() {
-			continue
-		}
-		normalFeeds = append(normalFeeds, f)
-		for _, i := range f.Items {
-			i.FeedName = f.Name
-			if i.IsToday() {
-				todayFeed.Items = append(todayFeed.Items, i)
-			}
-			if !i.IsRead {
-				unreadFeed.Items = append(unreadFeed.Items, i)
-			}
-			if i.IsStarred {
-				starredFeed.Items = append(starredFeed.Items, i)
-			}
+		if !f.IsSmart() {
+			normalFeeds = append(normalFeeds, f)
 		}
 	}
 
-	slices.SortFunc(todayFeed.Items, func(a, b rss.FeedItem) int {
-		return b.PublishedAt.Compare(a.PublishedAt)
-	})
-	slices.SortFunc(unreadFeed.Items, func(a, b rss.FeedItem) int {
-		return b.PublishedAt.Compare(a.PublishedAt)
-	})
-	slices.SortFunc(starredFeed.Items, func(a, b rss.FeedItem) int {
-		return b.PublishedAt.Compare(a.PublishedAt)
-	})
-
-	allFeeds := []rss.Feed{todayFeed, unreadFeed, starredFeed}
+	allFeeds := []rss.Feed{
+		rss.NewTodayFeed(),
+		rss.NewUnreadFeed(),
+		rss.NewStarredFeed(),
+		rss.NewBrokenFeed(normalFeeds),
+	}
+	for i := range allFeeds {
+		p.counts.Apply(&allFeeds[i])
+	}
 	allFeeds = append(allFeeds, normalFeeds...)
 	p.listView.setItems(allFeeds)
 }
@@ -325,6 +477,13 @@ func (p Feed) NormalFeeds() []rss.Feed {
 	})
 }
 
+// DueFeeds returns the feeds scheduled to refresh by t.
+func (p Feed) DueFeeds(t time.Time) []rss.Feed {
+	return slices.DeleteFunc(p.NormalFeeds(), func(f rss.Feed) bool {
+		return !f.IsDue(t)
+	})
+}
+
 func (p *Feed) SetFocused(focused bool) {
 	p.isFocused = focused
 }
@@ -345,7 +504,7 @@ func (p Feed) footView() string {
 		return ""
 	}
 
-	index := p.listView.index() - 3 //nolint:mnd // smart feeds length
+	index := p.listView.index() - smartFeedsLength
 	if index < 0 || index > total {
 		return ""
 	}
@@ -18,6 +18,14 @@ import (
 	"github.com/pkg/browser"
 )
 
+const (
+	// itemPageSize is how many items are loaded at a time.
+	itemPageSize = 200
+	// loadMoreThreshold is how close to the last loaded item the
+	// cursor gets before the next page is loaded.
+	loadMoreThreshold = 20
+)
+
 type Item struct {
 	height    int
 	cfg       *config.App
@@ -26,6 +34,14 @@ type Item struct {
 	feed      *rss.Feed
 	listView  listView[rss.FeedItem]
 	isFocused bool
+	// previewed is the item the preview last rendered, an update is
+	// seen only once the preview got what changed.
+	previewed int64
+
+	// query is the last page asked for, pages of other queries are stale.
+	query   rss.ItemQuery
+	loading bool
+	hasMore bool
 }
 
 func NewItem(cfg *config.App, logger *slog.Logger, repo rss.Repo) Item {
@@ -48,6 +64,48 @@ func (p Item) Update(msg tea.Msg) (Item, tea.Cmd) {
 	switch msg := msg.(type) {
 	case message.SelectFeed:
 		return p, p.onSelectFeed(msg)
+	case message.LoadItems:
+		return p, p.onLoadItems(msg)
+	case message.LoadCounts:
+		// The title shows the unread count of the feed.
+		if p.feed != nil && msg.IsSuccessful() {
+			msg.Counts.Apply(p.feed)
+		}
+		return p, nil
+	case message.ToogleRead:
+		p.update(func(f *rss.Feed) {
+			f.ToogleRead(msg.ItemID)
+		})
+		return p, nil
+	case message.MarkAllRead:
+		p.update(func(f *rss.Feed) {
+			f.MarkAllRead(msg.Feed)
+		})
+		return p, nil
+	case message.ToogleStarred:
+		p.update(func(f *rss.Feed) {
+			f.ToogleStarred(msg.ItemID)
+		})
+		return p, nil
+	case message.ParseMD:
+		if !msg.IsSuccessful() {
+			return p, nil
+		}
+		p.previewed = msg.FeedItem.ID
+		if p.isFocused {
+			return p, p.sendUpdateSeenCmd()
+		}
+		return p, nil
+	case message.MarkUpdateSeen:
+		p.update(func(f *rss.Feed) {
+			f.MarkUpdateSeen(msg.ItemID)
+		})
+		return p, nil
+	case message.MarkPlayed:
+		p.update(func(f *rss.Feed) {
+			f.MarkPlayed(msg.ItemID)
+		})
+		return p, nil
 	case tea.KeyMsg:
 		if key.Matches(msg, p.cfg.KeyMap.ToogleRead) {
 			return p, p.sendToogleReadCmd()
@@ -55,10 +113,19 @@ func (p Item) Update(msg tea.Msg) (Item, tea.Cmd) {
 		if key.Matches(msg, p.cfg.KeyMap.ToogleStarred) {
 			return p, p.sendToogleStarredCmd()
 		}
+		if key.Matches(msg, p.cfg.KeyMap.DeleteFeed) {
+			return p, p.onDeleteFeedKeyMsg()
+		}
 		if key.Matches(msg, p.cfg.KeyMap.Open) {
 			p.onOpenKeyMsg()
 			return p, nil
 		}
+		if key.Matches(msg, p.cfg.KeyMap.Play) {
+			return p, p.sendQueuePlayCmd()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.Download) {
+			return p, p.sendQueueDownloadCmd()
+		}
 	}
 
 	p.listView, cmd = p.listView.Update(msg)
@@ -72,42 +139,148 @@ func (p Item) Update(msg tea.Msg) (Item, tea.Cmd) {
 	// When selected item is not read, send toogle read message
 	cmds = append(cmds, p.sendReadCmd())
 
+	cmds = append(cmds, p.loadMore())
+
 	return p, tea.Batch(cmds...)
 }
 
 func (p *Item) onSelectFeed(msg message.SelectFeed) tea.Cmd {
-	var cmd tea.Cmd
-
 	if msg.Feed == nil {
-		p.feed = msg.Feed
+		p.feed = nil
+		p.loading = false
 		p.listView.setItems([]rss.FeedItem{})
-		return cmd
+		return nil
 	}
 
-	slices.SortFunc(msg.Feed.Items, func(a, b rss.FeedItem) int {
-		return b.PublishedAt.Compare(a.PublishedAt)
-	})
-	p.listView.setItems(msg.Feed.Items)
+	var cmds []tea.Cmd
+
+	f := *msg.Feed
+	isChanged := p.feed == nil || !p.feed.Is(f)
+	// Items of the broken feed are not stored, they come with the feed.
+	isBroken := f.Smart == rss.SmartBroken
+	if !isChanged && !isBroken {
+		f.Items = p.feed.Items
+	}
+	p.feed = &f
+	if isChanged || isBroken {
+		p.loading = false
+		p.listView.setItems(f.Items)
+	}
 
 	// When selected feed is changed, unselect item
-	if p.feed == nil || msg.Feed.ID != p.feed.ID {
+	if isChanged {
 		p.listView.selectByIndex(-1)
-		cmd = func() tea.Msg {
+		cmd := func() tea.Msg {
 			return message.NewSelectFeedItem(nil)
 		}
-	}
-
-	p.feed = msg.Feed
+		cmds = append(cmds, cmd)
 
-	if p.feed != nil {
-		if p.feed.IsSmart() {
+		if f.IsSmart() {
 			p.listView.setDelegate(delegate.NewSmartItem(p.cfg.Theme))
 		} else {
 			p.listView.setDelegate(delegate.NewItem(p.cfg.Theme))
 		}
 	}
 
-	return cmd
+	if !isBroken && (isChanged || msg.Reload || msg.Item != nil) {
+		// Reload as many items as are shown, the cursor stays in place.
+		q := rss.NewItemQuery(f, max(itemPageSize, len(f.Items)))
+		if msg.Item != nil {
+			q = q.Until(*msg.Item)
+		}
+		cmds = append(cmds, p.load(q))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (p *Item) onLoadItems(msg message.LoadItems) tea.Cmd {
+	if p.feed == nil || !p.loading || msg.Query != p.query {
+		return nil
+	}
+	p.loading = false
+
+	if msg.IsFailed() {
+		p.logger.Error("load items failed", "feed id", msg.Query.FeedID, "err", msg.Err)
+		return message.ErrTipsCmd("Load items failed", msg.Err, true)
+	}
+
+	// A page down to an item may be followed by more.
+	p.hasMore = len(msg.Items) == msg.Query.Limit || msg.Query.UntilID != 0
+
+	// Next pages are appended, the first page replaces the items.
+	if msg.Query.AfterID != 0 {
+		p.feed.Items = append(p.feed.Items, msg.Items...)
+		p.listView.setItems(p.feed.Items)
+		return nil
+	}
+
+	selected := p.listView.selectedItem()
+	p.feed.Items = msg.Items
+	p.listView.setItems(p.feed.Items)
+
+	// An opened item is selected, it ends the page.
+	if msg.Query.UntilID != 0 {
+		return p.selectItem(msg.Query.UntilID)
+	}
+	if selected == nil {
+		return nil
+	}
+
+	// Keep the cursor on the selected item, new items come first.
+	idx := slices.IndexFunc(p.feed.Items, func(i rss.FeedItem) bool {
+		return i.ID == selected.ID
+	})
+	if idx >= 0 {
+		p.listView.selectByIndex(idx)
+	}
+	return nil
+}
+
+// selectItem moves the cursor to the item and shows it in preview.
+func (p *Item) selectItem(id int64) tea.Cmd {
+	idx := slices.IndexFunc(p.feed.Items, func(i rss.FeedItem) bool {
+		return i.ID == id
+	})
+	if idx < 0 {
+		return nil
+	}
+	p.listView.selectByIndex(idx)
+
+	selected := p.listView.selectedItem()
+	cmd := func() tea.Msg {
+		return message.NewSelectFeedItem(selected)
+	}
+	return tea.Batch(cmd, p.sendReadCmd())
+}
+
+// loadMore loads the next page when the cursor is close to the end.
+func (p *Item) loadMore() tea.Cmd {
+	if p.feed == nil || p.loading || !p.hasMore || len(p.feed.Items) == 0 {
+		return nil
+	}
+	if p.listView.index() < len(p.feed.Items)-loadMoreThreshold {
+		return nil
+	}
+
+	q := p.query.Next(p.feed.Items[len(p.feed.Items)-1])
+	q.Limit = itemPageSize
+	return p.load(q)
+}
+
+func (p *Item) load(q rss.ItemQuery) tea.Cmd {
+	p.query = q
+	p.loading = true
+	return message.LoadItemsCmd(q, p.repo)
+}
+
+// update changes the loaded items, the cursor stays in place.
+func (p *Item) update(fn func(f *rss.Feed)) {
+	if p.feed == nil {
+		return
+	}
+	fn(p.feed)
+	p.listView.setItems(p.feed.Items)
 }
 
 func (p *Item) SetFocused(focused bool) tea.Cmd {
@@ -115,7 +288,9 @@ func (p *Item) SetFocused(focused bool) tea.Cmd {
 	var cmds []tea.Cmd
 
 	p.isFocused = focused
-	if p.isFocused && p.listView.selectedItem() == nil && len(p.listView.items()) > 0 {
+	// An opened item is selected once it's loaded.
+	isOpening := p.loading && p.query.UntilID != 0
+	if p.isFocused && !isOpening && p.listView.selectedItem() == nil && len(p.listView.items()) > 0 {
 		p.listView.selectByIndex(0)
 		cmd = func() tea.Msg {
 			return message.NewSelectFeedItem(p.listView.selectedItem())
@@ -133,18 +308,33 @@ func (p *Item) SetFocused(focused bool) tea.Cmd {
 }
 
 func (p Item) sendReadCmd() tea.Cmd {
-	var cmd tea.Cmd
 	i := p.listView.selectedItem()
-	if p.feed != nil && i != nil && !i.IsRead {
-		cmd = message.ToogleReadCmd(i.ID, p.repo)
+	if p.feed == nil || i == nil || i.IsBrokenFeed() {
+		return nil
 	}
-	return cmd
+
+	var cmds []tea.Cmd
+	if !i.IsRead {
+		cmds = append(cmds, message.ToogleReadCmd(i.ID, p.repo))
+	}
+	cmds = append(cmds, p.sendUpdateSeenCmd())
+	return tea.Batch(cmds...)
+}
+
+// sendUpdateSeenCmd marks the update of the selected item seen when the
+// preview rendered it, otherwise the preview does once it has.
+func (p Item) sendUpdateSeenCmd() tea.Cmd {
+	i := p.listView.selectedItem()
+	if p.feed == nil || i == nil || i.IsBrokenFeed() || !i.IsUpdated || i.ID != p.previewed {
+		return nil
+	}
+	return message.MarkUpdateSeenCmd(i.ID, p.repo)
 }
 
 func (p Item) sendToogleReadCmd() tea.Cmd {
 	var cmd tea.Cmd
 	i := p.listView.selectedItem()
-	if p.feed != nil && i != nil {
+	if p.feed != nil && i != nil && !i.IsBrokenFeed() {
 		cmd = message.ToogleReadCmd(i.ID, p.repo)
 	}
 	return cmd
@@ -153,12 +343,47 @@ func (p Item) sendToogleReadCmd() tea.Cmd {
 func (p Item) sendToogleStarredCmd() tea.Cmd {
 	var cmd tea.Cmd
 	i := p.listView.selectedItem()
-	if p.feed != nil && i != nil {
+	if p.feed != nil && i != nil && !i.IsBrokenFeed() {
 		cmd = message.ToogleStarredCmd(i.ID, p.repo)
 	}
 	return cmd
 }
 
+func (p Item) sendQueuePlayCmd() tea.Cmd {
+	var cmd tea.Cmd
+	i := p.listView.selectedItem()
+	if p.feed != nil && i != nil && !i.IsBrokenFeed() {
+		cmd = func() tea.Msg {
+			return message.NewQueuePlay(*i)
+		}
+	}
+	return cmd
+}
+
+func (p Item) sendQueueDownloadCmd() tea.Cmd {
+	var cmd tea.Cmd
+	i := p.listView.selectedItem()
+	if p.feed != nil && i != nil && !i.IsBrokenFeed() {
+		cmd = func() tea.Msg {
+			return message.NewQueueDownload(*i)
+		}
+	}
+	return cmd
+}
+
+// onDeleteFeedKeyMsg deletes the feed an item of the broken smart feed
+// stands for, other items belong to feeds deleted in feed panel.
+func (p Item) onDeleteFeedKeyMsg() tea.Cmd {
+	var cmd tea.Cmd
+	if i := p.listView.selectedItem(); i != nil && i.IsBrokenFeed() {
+		f := rss.Feed{ID: i.FeedID, Name: i.FeedName, FeedURL: i.Link}
+		cmd = func() tea.Msg {
+			return message.NewDeleteFeedInitial(f)
+		}
+	}
+	return cmd
+}
+
 func (p Item) onOpenKeyMsg() {
 	i := p.listView.selectedItem()
 	if i == nil {
@@ -195,8 +420,8 @@ func (p Item) titleView() string {
 	title := p.feed.Name
 
 	unread := ""
-	if p.feed.UnreadCount() > 0 {
-		unread = fmt.Sprintf(" [%d]", p.feed.UnreadCount())
+	if p.feed.UnreadCount > 0 {
+		unread = fmt.Sprintf(" [%d]", p.feed.UnreadCount)
 	}
 
 	titleStyle := lipgloss.NewStyle().Foreground(p.cfg.Theme.FeedTitleActive).
@@ -18,12 +18,13 @@ import (
 type Preview struct {
 	cfg       *config.App
 	logger    *slog.Logger
+	repo      rss.Repo
 	viewport  viewport.Model
 	isFocused bool
 	item      *rss.FeedItem
 }
 
-func NewPreview(cfg *config.App, logger *slog.Logger) Preview {
+func NewPreview(cfg *config.App, logger *slog.Logger, repo rss.Repo) Preview {
 	vp := viewport.New(0, 0)
 	vp.KeyMap = viewport.KeyMap{
 		PageDown: cfg.KeyMap.NextPage,
@@ -34,6 +35,7 @@ func NewPreview(cfg *config.App, logger *slog.Logger) Preview {
 	return Preview{
 		cfg:      cfg,
 		logger:   logger,
+		repo:     repo,
 		viewport: vp,
 	}
 }
@@ -50,11 +52,22 @@ func (p Preview) Update(msg tea.Msg) (Preview, tea.Cmd) {
 	case message.ParseMD:
 		p.onParseMDMsg(msg)
 		return p, nil
+	case message.ExtractFullContent:
+		return p, p.onExtractFullContentMsg(msg)
 	case tea.KeyMsg:
 		if key.Matches(msg, p.cfg.KeyMap.Open) {
 			p.onOpenKeyMsg()
 			return p, nil
 		}
+		if key.Matches(msg, p.cfg.KeyMap.FullContent) {
+			return p, p.onFullContentKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.Play) {
+			return p, p.onPlayKeyMsg()
+		}
+		if key.Matches(msg, p.cfg.KeyMap.Download) {
+			return p, p.onDownloadKeyMsg()
+		}
 		if key.Matches(msg, p.cfg.KeyMap.Start) {
 			p.viewport.SetYOffset(0)
 			return p, nil
@@ -75,7 +88,7 @@ func (p *Preview) onSelectFeedItemMsg(msg message.SelectFeedItem) tea.Cmd {
 
 	var cmd tea.Cmd
 	if p.item != nil {
-		cmd = message.ParseMDCmd(*p.item, p.viewport.Width)
+		cmd = message.ParseMDCmd(*p.item, p.repo, p.viewport.Width)
 	}
 	return cmd
 }
@@ -99,10 +112,57 @@ func (p *Preview) onParseMDMsg(msg message.ParseMD) {
 	}
 
 	if msg.IsSuccessful() {
+		// The item of the list has no content, keep the loaded one.
+		i := msg.FeedItem
+		p.item = &i
 		p.viewport.SetContent(style.Render(msg.MD))
 	}
 }
 
+func (p *Preview) onExtractFullContentMsg(msg message.ExtractFullContent) tea.Cmd {
+	if !msg.IsSuccessful() || p.item == nil || p.item.ID != msg.FeedItem.ID {
+		return nil
+	}
+
+	i := *p.item
+	i.FullContent = msg.FeedItem.FullContent
+	p.item = &i
+	return message.ParseMDCmd(i, p.repo, p.viewport.Width)
+}
+
+func (p Preview) onFullContentKeyMsg() tea.Cmd {
+	if p.item == nil || p.item.Link == "" || p.item.IsBrokenFeed() {
+		return nil
+	}
+
+	i := *p.item
+	return func() tea.Msg {
+		return message.NewExtractFullContentInitial(i)
+	}
+}
+
+func (p Preview) onPlayKeyMsg() tea.Cmd {
+	if p.item == nil || p.item.IsBrokenFeed() {
+		return nil
+	}
+
+	i := *p.item
+	return func() tea.Msg {
+		return message.NewQueuePlay(i)
+	}
+}
+
+func (p Preview) onDownloadKeyMsg() tea.Cmd {
+	if p.item == nil || p.item.IsBrokenFeed() {
+		return nil
+	}
+
+	i := *p.item
+	return func() tea.Msg {
+		return message.NewQueueDownload(i)
+	}
+}
+
 func (p Preview) onOpenKeyMsg() {
 	if p.item == nil {
 		return
@@ -10,6 +10,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lakerszhy/rssx/internal/app"
 	"github.com/lakerszhy/rssx/internal/config"
+	"github.com/lakerszhy/rssx/internal/download"
+	"github.com/lakerszhy/rssx/internal/player"
+	"github.com/lakerszhy/rssx/internal/rss"
 	"github.com/lakerszhy/rssx/internal/store"
 	_ "modernc.org/sqlite"
 )
@@ -41,13 +44,30 @@ func run() error {
 		return err
 	}
 
+	fetcher, err := rss.NewFetcher(rss.FetcherOptions{
+		UserAgent:   cfg.HTTP.UserAgent,
+		Proxy:       cfg.HTTP.Proxy,
+		Timeout:     cfg.HTTP.Timeout,
+		MaxBodySize: cfg.HTTP.MaxBodySize,
+	})
+	if err != nil {
+		return err
+	}
+
 	store, err := store.New(dir, logger)
 	if err != nil {
 		return err
 	}
 	defer store.Close()
 
-	p := tea.NewProgram(app.New(dir, cfg, logger, store, version),
+	manager := download.NewManager(cfg.Download.Dir, cfg.Download.MaxConcurrent,
+		fetcher, store, logger)
+	defer manager.Close()
+
+	player := player.New(cfg.Player, logger)
+	defer player.Close()
+
+	p := tea.NewProgram(app.New(dir, cfg, logger, store, fetcher, manager, player, version),
 		tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err = p.Run(); err != nil {
 		return err